@@ -0,0 +1,249 @@
+package go_swagger_ui
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ErrUnauthenticated can be returned by an Authorizer to signal that a request did not contain
+// valid credentials. The handler will respond with status code 401 (Unauthorized) and, if the
+// Authorizer implements Challenger, a WWW-Authenticate header.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authorizer decides whether a request is allowed to access Swagger UI.
+// If allowed is false and err is nil, the handler responds with status code 403 (Forbidden).
+// If err wraps ErrUnauthenticated, the handler responds with status code 401 (Unauthorized).
+// Any other error results in status code 500 (Internal Server Error).
+type Authorizer interface {
+	Authorize(r *http.Request) (allowed bool, err error)
+}
+
+// AuthorizerFunc is an adapter to allow the use of ordinary functions as Authorizer.
+type AuthorizerFunc func(r *http.Request) (allowed bool, err error)
+
+// Authorize calls f(r).
+func (f AuthorizerFunc) Authorize(r *http.Request) (bool, error) {
+	return f(r)
+}
+
+// Challenger can be implemented by an Authorizer to provide the value of the WWW-Authenticate
+// header that is sent along with 401 (Unauthorized) responses.
+type Challenger interface {
+	Challenge() string
+}
+
+// WithAuthorizer restricts access to Swagger UI (including all of its asset files)
+// to requests that are allowed by the given Authorizer. See BasicAuth, BasicAuthFromHtpasswd
+// and BearerToken for ready-made implementations.
+func WithAuthorizer(authorizer Authorizer) Option {
+	return func(cfg *uiConfig) {
+		cfg.authorizer = authorizer
+	}
+}
+
+// authorize checks the request against the configured Authorizer and writes an error
+// response if access is denied. It returns true if the request may be processed further.
+func authorize(w http.ResponseWriter, r *http.Request, authorizer Authorizer) bool {
+	allowed, err := authorizer.Authorize(r)
+
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		if challenger, ok := authorizer.(Challenger); ok {
+			w.Header().Set("WWW-Authenticate", challenger.Challenge())
+		}
+		sendStatus(w, http.StatusUnauthorized)
+		return false
+	case err != nil:
+		slog.Error("failed to authorize request", "err", err.Error())
+		sendStatus(w, http.StatusInternalServerError)
+		return false
+	case !allowed:
+		sendStatus(w, http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+func sendStatus(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	fmt.Fprintln(w, http.StatusText(status))
+}
+
+type basicAuth struct {
+	realm string
+	check func(username, password string) bool
+}
+
+func (a *basicAuth) Authorize(r *http.Request) (bool, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok || !a.check(username, password) {
+		return false, ErrUnauthenticated
+	}
+
+	return true, nil
+}
+
+func (a *basicAuth) Challenge() string {
+	return fmt.Sprintf("Basic realm=%q, charset=\"UTF-8\"", a.realm)
+}
+
+// BasicAuth returns an Authorizer that requires HTTP basic authentication. The credentials
+// map contains plain text passwords keyed by username. Credentials are compared in constant time.
+func BasicAuth(realm string, credentials map[string]string) Authorizer {
+	type hashedCredentials struct {
+		username, password [sha256.Size]byte
+	}
+
+	// Hashing credentials makes all values equal length, so that constant time comparison
+	// does not leak the length of usernames or passwords.
+	hashed := make([]hashedCredentials, 0, len(credentials))
+	for username, password := range credentials {
+		hashed = append(hashed, hashedCredentials{
+			username: sha256.Sum256([]byte(username)),
+			password: sha256.Sum256([]byte(password)),
+		})
+	}
+
+	return &basicAuth{
+		realm: realm,
+		check: func(username, password string) bool {
+			usernameHash := sha256.Sum256([]byte(username))
+			passwordHash := sha256.Sum256([]byte(password))
+
+			// We intentionally compare against all entries to not leak which usernames exist.
+			match := 0
+			for _, c := range hashed {
+				usernameMatch := subtle.ConstantTimeCompare(usernameHash[:], c.username[:])
+				passwordMatch := subtle.ConstantTimeCompare(passwordHash[:], c.password[:])
+				match |= usernameMatch & passwordMatch
+			}
+
+			return match == 1
+		},
+	}
+}
+
+// BasicAuthFromHtpasswd returns an Authorizer that requires HTTP basic authentication
+// using the credentials from an htpasswd file (e.g., created with "htpasswd -B").
+// Only bcrypt password hashes are supported.
+func BasicAuthFromHtpasswd(realm string, htpasswd io.Reader) (Authorizer, error) {
+	hashes, err := parseHtpasswd(htpasswd)
+	if err != nil {
+		return nil, err
+	}
+
+	// A dummy hash is used for unknown users, so that response times
+	// do not reveal which usernames exist.
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte("dummy"), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate dummy hash: %w", err)
+	}
+
+	return &basicAuth{
+		realm: realm,
+		check: func(username, password string) bool {
+			hash, ok := hashes[username]
+			if !ok {
+				bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+				return false
+			}
+
+			return bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+		},
+	}, nil
+}
+
+// BasicAuthFromHtpasswdFile is like BasicAuthFromHtpasswd but reads the htpasswd file from
+// the given path. The file is only read once.
+func BasicAuthFromHtpasswdFile(realm, path string) (Authorizer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening htpasswd file: %w", err)
+	}
+	defer file.Close()
+
+	return BasicAuthFromHtpasswd(realm, file)
+}
+
+func parseHtpasswd(r io.Reader) (map[string][]byte, error) {
+	hashes := make(map[string][]byte)
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		username, hash, ok := strings.Cut(line, ":")
+		if !ok || username == "" {
+			return nil, fmt.Errorf("invalid htpasswd entry in line %d", lineNumber)
+		}
+
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("unsupported password hash for user %q in line %d (only bcrypt is supported)",
+				username, lineNumber)
+		}
+
+		hashes[username] = []byte(hash)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading htpasswd file: %w", err)
+	}
+
+	return hashes, nil
+}
+
+type bearerToken struct {
+	realm  string
+	hashes [][sha256.Size]byte
+}
+
+// BearerToken returns an Authorizer that requires requests to contain one of the given static
+// tokens in the Authorization header (e.g., "Authorization: Bearer my-token").
+// Tokens are compared in constant time.
+func BearerToken(realm string, tokens ...string) Authorizer {
+	hashes := make([][sha256.Size]byte, 0, len(tokens))
+	for _, token := range tokens {
+		hashes = append(hashes, sha256.Sum256([]byte(token)))
+	}
+
+	return &bearerToken{realm: realm, hashes: hashes}
+}
+
+func (a *bearerToken) Authorize(r *http.Request) (bool, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return false, ErrUnauthenticated
+	}
+
+	tokenHash := sha256.Sum256([]byte(strings.TrimSpace(token)))
+
+	match := 0
+	for _, hash := range a.hashes {
+		match |= subtle.ConstantTimeCompare(tokenHash[:], hash[:])
+	}
+
+	if match != 1 {
+		return false, ErrUnauthenticated
+	}
+
+	return true, nil
+}
+
+func (a *bearerToken) Challenge() string {
+	return fmt.Sprintf("Bearer realm=%q", a.realm)
+}
@@ -0,0 +1,93 @@
+package go_swagger_ui
+
+import (
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthorizers(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	htpasswdAuth, err := BasicAuthFromHtpasswd("docs", strings.NewReader("# users\nadmin:"+string(hash)+"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basicChallenge := `Basic realm="docs", charset="UTF-8"`
+	bearerChallenge := `Bearer realm="docs"`
+
+	tests := []struct {
+		name              string
+		authorizer        Authorizer
+		authorization     string
+		expectedStatus    int
+		expectedChallenge string
+	}{
+		{"basic auth without credentials", BasicAuth("docs", map[string]string{"admin": "secret"}), "", http.StatusUnauthorized, basicChallenge},
+		{"basic auth with wrong password", BasicAuth("docs", map[string]string{"admin": "secret"}), basic("admin", "wrong"), http.StatusUnauthorized, basicChallenge},
+		{"basic auth with unknown user", BasicAuth("docs", map[string]string{"admin": "secret"}), basic("root", "secret"), http.StatusUnauthorized, basicChallenge},
+		{"basic auth with bearer token", BasicAuth("docs", map[string]string{"admin": "secret"}), "Bearer secret", http.StatusUnauthorized, basicChallenge},
+		{"basic auth with valid credentials", BasicAuth("docs", map[string]string{"admin": "secret"}), basic("admin", "secret"), http.StatusOK, ""},
+		{"htpasswd without credentials", htpasswdAuth, "", http.StatusUnauthorized, basicChallenge},
+		{"htpasswd with wrong password", htpasswdAuth, basic("admin", "wrong"), http.StatusUnauthorized, basicChallenge},
+		{"htpasswd with unknown user", htpasswdAuth, basic("root", "secret"), http.StatusUnauthorized, basicChallenge},
+		{"htpasswd with valid credentials", htpasswdAuth, basic("admin", "secret"), http.StatusOK, ""},
+		{"bearer token without token", BearerToken("docs", "token-1", "token-2"), "", http.StatusUnauthorized, bearerChallenge},
+		{"bearer token with wrong token", BearerToken("docs", "token-1", "token-2"), "Bearer token-3", http.StatusUnauthorized, bearerChallenge},
+		{"bearer token with basic auth", BearerToken("docs", "token-1", "token-2"), basic("token-1", ""), http.StatusUnauthorized, bearerChallenge},
+		{"bearer token with valid token", BearerToken("docs", "token-1", "token-2"), "Bearer token-2", http.StatusOK, ""},
+		{"bearer token with lower case scheme", BearerToken("docs", "token-1"), "bearer token-1", http.StatusOK, ""},
+		{"authorizer denying access", AuthorizerFunc(func(r *http.Request) (bool, error) { return false, nil }), "", http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(WithSpec([]byte(testSpec)), WithAuthorizer(tt.authorizer))
+
+			req := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if challenge := rec.Header().Get("WWW-Authenticate"); challenge != tt.expectedChallenge {
+				t.Errorf("expected WWW-Authenticate %q, got %q", tt.expectedChallenge, challenge)
+			}
+		})
+	}
+}
+
+func TestBasicAuthFromHtpasswdRejectsUnsupportedHashes(t *testing.T) {
+	tests := []struct {
+		name, htpasswd, err string
+	}{
+		{"MD5 hash", "admin:$apr1$salt$hash\n", `unsupported password hash for user "admin" in line 1`},
+		{"missing separator", "# users\nadmin\n", "invalid htpasswd entry in line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BasicAuthFromHtpasswd("docs", strings.NewReader(tt.htpasswd))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func basic(username, password string) string {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth(username, password)
+	return req.Header.Get("Authorization")
+}
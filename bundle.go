@@ -0,0 +1,400 @@
+package go_swagger_ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BundleMode controls how Bundle replaces references to other files.
+type BundleMode int
+
+const (
+	// BundleHoist copies referenced documents into the components section of the spec
+	// (or into "definitions", "parameters" and "responses" for Swagger 2.0) and replaces
+	// external references with local references. Path items are always inlined.
+	BundleHoist BundleMode = iota
+	// BundleInline replaces external references with the referenced documents.
+	// Circular references between files cannot be inlined and cause an error.
+	BundleInline
+)
+
+// ErrCircularReference is returned by Bundle if external references form a cycle
+// and BundleInline is used.
+var ErrCircularReference = errors.New("circular reference")
+
+// WithSpecFS sets the spec to the document with the given name in fsys. References to other
+// files in fsys (e.g., "$ref: ./schemas/pet.yaml") are resolved when the handler is created
+// (see Bundle and WithBundleMode). Overrides the spec set with WithSpec.
+func WithSpecFS(fsys fs.FS, name string) Option {
+	return func(cfg *uiConfig) {
+		cfg.specFS = fsys
+		cfg.specFSName = name
+	}
+}
+
+// WithBundleMode sets how references to other files are resolved for specs set with
+// WithSpecFS or WithSpecFilePath. Default is BundleHoist.
+func WithBundleMode(mode BundleMode) Option {
+	return func(cfg *uiConfig) {
+		cfg.bundleMode = mode
+	}
+}
+
+// Bundle reads the spec with the given name from fsys and resolves all references to other
+// files in fsys into a single JSON document. References are resolved relative to the file
+// they appear in and must not point outside of fsys. References to URLs are left untouched.
+// If the spec does not contain references to other files, it is returned unchanged.
+func Bundle(fsys fs.FS, name string, mode BundleMode) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read spec: %w", err)
+	}
+
+	b := &bundler{
+		fsys:    fsys,
+		root:    path.Clean(name),
+		mode:    mode,
+		docs:    make(map[string]map[string]any),
+		hoisted: make(map[string]string),
+		added:   make(map[string]map[string]any),
+	}
+
+	root, err := b.load(b.root, data)
+	if err != nil {
+		return nil, err
+	}
+	b.swagger2 = isSwagger2(root)
+
+	resolved, err := b.resolve(root, b.root, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !b.changed {
+		return data, nil
+	}
+
+	spec := resolved.(map[string]any)
+	for section, components := range b.added {
+		target := spec
+		for _, key := range strings.Split(section, "/") {
+			child, ok := target[key].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				target[key] = child
+			}
+			target = child
+		}
+
+		for name, component := range components {
+			target[name] = component
+		}
+	}
+
+	return marshalSpec(spec)
+}
+
+type bundler struct {
+	fsys     fs.FS
+	root     string
+	mode     BundleMode
+	swagger2 bool
+	changed  bool
+
+	// docs contains all parsed documents by file name.
+	docs map[string]map[string]any
+	// hoisted maps external references ("file#fragment") to the local references they were hoisted to.
+	hoisted map[string]string
+	// added contains hoisted components by section (e.g., "components/schemas").
+	added map[string]map[string]any
+	// stack contains the external references that are currently inlined.
+	stack []string
+}
+
+func (b *bundler) load(name string, data []byte) (map[string]any, error) {
+	if doc, ok := b.docs[name]; ok {
+		return doc, nil
+	}
+
+	if data == nil {
+		var err error
+		if data, err = fs.ReadFile(b.fsys, name); err != nil {
+			return nil, fmt.Errorf("cannot read referenced file: %w", err)
+		}
+	}
+
+	jsonData, err := yamlOrJSONToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", name, err)
+	}
+
+	doc, err := parseSpec(jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", name, err)
+	}
+
+	b.docs[name] = doc
+
+	return doc, nil
+}
+
+// resolve returns a copy of value in which all external references are resolved. The file is the
+// name of the file value belongs to and the pointer is the location of value in the bundled spec.
+func (b *bundler) resolve(value any, file string, pointer []string) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			return b.resolveRef(v, ref, file, pointer)
+		}
+
+		result := make(map[string]any, len(v))
+		for key, elem := range v {
+			resolved, err := b.resolve(elem, file, append(pointer[:len(pointer):len(pointer)], key))
+			if err != nil {
+				return nil, err
+			}
+			result[key] = resolved
+		}
+		return result, nil
+	case []any:
+		result := make([]any, len(v))
+		for idx, elem := range v {
+			resolved, err := b.resolve(elem, file, append(pointer[:len(pointer):len(pointer)], strconv.Itoa(idx)))
+			if err != nil {
+				return nil, err
+			}
+			result[idx] = resolved
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+func (b *bundler) resolveRef(obj map[string]any, ref, file string, pointer []string) (any, error) {
+	// References to URLs are resolved by Swagger UI.
+	if u, err := url.Parse(ref); err == nil && u.Scheme != "" {
+		return obj, nil
+	}
+
+	refFile, fragment, _ := strings.Cut(ref, "#")
+	if refFile == "" {
+		refFile = file
+	} else {
+		unescaped, err := url.PathUnescape(refFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference %q in %s: %w", ref, file, err)
+		}
+
+		refFile = path.Join(path.Dir(file), unescaped)
+		if !fs.ValidPath(refFile) {
+			return nil, fmt.Errorf("reference %q in %s points outside of the file system", ref, file)
+		}
+	}
+
+	// Local references of the root document remain valid in the bundled spec.
+	if refFile == b.root {
+		if file != b.root {
+			b.changed = true
+		}
+		return b.withRef(obj, "#"+fragment), nil
+	}
+
+	b.changed = true
+	key := refFile + "#" + fragment
+
+	if b.mode == BundleHoist {
+		if localRef, ok := b.hoisted[key]; ok {
+			return b.withRef(obj, localRef), nil
+		}
+
+		if section := b.componentSection(fragment, pointer); section != "" {
+			return b.hoist(obj, key, refFile, fragment, section)
+		}
+	}
+
+	for idx, stackKey := range b.stack {
+		if stackKey == key {
+			return nil, fmt.Errorf("%w: %s", ErrCircularReference, strings.Join(append(b.stack[idx:], key), " -> "))
+		}
+	}
+
+	target, err := b.target(refFile, fragment)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve reference %q in %s: %w", ref, file, err)
+	}
+
+	b.stack = append(b.stack, key)
+	defer func() { b.stack = b.stack[:len(b.stack)-1] }()
+
+	resolved, err := b.resolve(target, refFile, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fields next to "$ref" (e.g., "description" in OpenAPI 3.1) override the fields of the target.
+	if resolvedObj, ok := resolved.(map[string]any); ok && len(obj) > 1 {
+		merged := make(map[string]any, len(resolvedObj)+len(obj))
+		for k, v := range resolvedObj {
+			merged[k] = v
+		}
+		for k, v := range obj {
+			if k != "$ref" {
+				merged[k] = v
+			}
+		}
+		return merged, nil
+	}
+
+	return resolved, nil
+}
+
+// hoist adds the target of an external reference to the given component section and returns
+// a local reference to it.
+func (b *bundler) hoist(obj map[string]any, key, refFile, fragment, section string) (any, error) {
+	target, err := b.target(refFile, fragment)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve reference %q: %w", key, err)
+	}
+
+	name := b.componentName(section, refFile, fragment)
+	localRef := "#/" + section + "/" + escapeJSONPointer(name)
+
+	// The reference is registered before the target is resolved, so that recursive references
+	// point to the hoisted component.
+	b.hoisted[key] = localRef
+	if b.added[section] == nil {
+		b.added[section] = make(map[string]any)
+	}
+	b.added[section][name] = nil
+
+	resolved, err := b.resolve(target, refFile, strings.Split(section+"/"+name, "/"))
+	if err != nil {
+		return nil, err
+	}
+	b.added[section][name] = resolved
+
+	return b.withRef(obj, localRef), nil
+}
+
+func (b *bundler) target(file, fragment string) (any, error) {
+	doc, err := b.load(file, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	target, ok := resolveRef(doc, "#"+fragment)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain %q", file, fragment)
+	}
+
+	return target, nil
+}
+
+// withRef returns a copy of a reference object with a new reference.
+func (b *bundler) withRef(obj map[string]any, ref string) map[string]any {
+	result := make(map[string]any, len(obj))
+	for key, value := range obj {
+		result[key] = value
+	}
+	result["$ref"] = ref
+
+	return result
+}
+
+// componentSection returns the component section an external reference should be hoisted into
+// (e.g., "components/schemas") or an empty string if the target must be inlined. The section
+// is taken from the fragment if it points to a component and derived from the location of the
+// reference otherwise.
+func (b *bundler) componentSection(fragment string, pointer []string) string {
+	if section, _, ok := splitComponentRef("#" + fragment); ok {
+		section = strings.TrimPrefix(section, "#/")
+		if b.allowedSection(section) {
+			return section
+		}
+	}
+
+	var parent, grandParent string
+	if len(pointer) > 0 {
+		parent = pointer[len(pointer)-1]
+	}
+	if len(pointer) > 1 {
+		grandParent = pointer[len(pointer)-2]
+	}
+
+	var section string
+	switch {
+	case len(pointer) == 2 && pointer[0] == "paths":
+		return "" // path items cannot be hoisted
+	case grandParent == "parameters":
+		section = "parameters"
+	case grandParent == "responses":
+		section = "responses"
+	case parent == "requestBody" || grandParent == "requestBodies":
+		section = "requestBodies"
+	case grandParent == "headers":
+		section = "headers"
+	case grandParent == "examples":
+		section = "examples"
+	case grandParent == "links":
+		section = "links"
+	case grandParent == "callbacks":
+		section = "callbacks"
+	case grandParent == "securitySchemes":
+		section = "securitySchemes"
+	default:
+		section = "schemas"
+	}
+
+	if b.swagger2 {
+		switch section {
+		case "schemas":
+			section = "definitions"
+		case "parameters", "responses":
+		default:
+			return ""
+		}
+		return section
+	}
+
+	return "components/" + section
+}
+
+func (b *bundler) allowedSection(section string) bool {
+	if b.swagger2 {
+		return section == "definitions" || section == "parameters" || section == "responses"
+	}
+
+	return strings.HasPrefix(section, "components/")
+}
+
+var invalidComponentNameChars = regexp.MustCompile(`[^a-zA-Z0-9.\-_]+`)
+
+// componentName returns a name for a hoisted component that is not used yet. It is derived
+// from the fragment of the reference or from the file name if the reference has no fragment.
+func (b *bundler) componentName(section, file, fragment string) string {
+	name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+	if tokens := strings.Split(fragment, "/"); fragment != "" && fragment != "/" {
+		name = unescapeJSONPointer(tokens[len(tokens)-1])
+	}
+
+	name = invalidComponentNameChars.ReplaceAllString(name, "_")
+
+	existing, _ := lookup(b.docs[b.root], strings.Split(section, "/")...).(map[string]any)
+
+	candidate := name
+	for idx := 2; ; idx++ {
+		_, inSpec := existing[candidate]
+		_, inAdded := b.added[section][candidate]
+		if !inSpec && !inAdded {
+			return candidate
+		}
+		candidate = name + strconv.Itoa(idx)
+	}
+}
@@ -0,0 +1,145 @@
+package go_swagger_ui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const bundleTestSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: ./models/pet.yaml
+`
+
+func TestBundleDetectsCircularReferences(t *testing.T) {
+	fsys := fstest.MapFS{
+		"openapi.yaml":      {Data: []byte(bundleTestSpec)},
+		"models/pet.yaml":   {Data: []byte("type: object\nproperties:\n  owner:\n    $ref: ./owner.yaml\n")},
+		"models/owner.yaml": {Data: []byte("type: object\nproperties:\n  pets:\n    type: array\n    items:\n      $ref: ./pet.yaml\n")},
+	}
+
+	_, err := Bundle(fsys, "openapi.yaml", BundleInline)
+	if !errors.Is(err, ErrCircularReference) {
+		t.Fatalf("expected circular reference error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "models/pet.yaml# -> models/owner.yaml# -> models/pet.yaml#") {
+		t.Errorf("expected the cycle in the error message, got %v", err)
+	}
+
+	// Hoisted components can reference each other.
+	data, err := Bundle(fsys, "openapi.yaml", BundleHoist)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := parseSpec(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ref := lookup(spec, "components", "schemas", "owner", "properties", "pets", "items", "$ref"); ref != "#/components/schemas/pet" {
+		t.Errorf("expected recursive reference to the hoisted component, got %v", ref)
+	}
+}
+
+func TestBundleComponentNames(t *testing.T) {
+	spec := `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+paths: {}
+components:
+  schemas:
+    pet:
+      type: string
+    Pets:
+      type: array
+      items:
+        $ref: ./models/pet.yaml
+    Owners:
+      type: array
+      items:
+        $ref: ./other/pet.yaml
+    Errors:
+      type: array
+      items:
+        $ref: "./shared/errors.yaml#/definitions/Error:NotFound"
+    Users:
+      type: array
+      items:
+        $ref: ./models/pet.yaml
+`
+
+	fsys := fstest.MapFS{
+		"openapi.yaml":       {Data: []byte(spec)},
+		"models/pet.yaml":    {Data: []byte("type: object\n")},
+		"other/pet.yaml":     {Data: []byte("type: object\n")},
+		"shared/errors.yaml": {Data: []byte("definitions:\n  Error:NotFound:\n    type: object\n")},
+	}
+
+	data, err := Bundle(fsys, "openapi.yaml", BundleHoist)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundled, err := parseSpec(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemas, _ := lookup(bundled, "components", "schemas").(map[string]any)
+	expected := []string{"Error_NotFound", "Errors", "Owners", "Pets", "Users", "pet", "pet2", "pet3"}
+	if names := sortedKeys(schemas); !slices.Equal(names, expected) {
+		t.Errorf("unexpected component names %v", names)
+	}
+
+	refs := map[string]string{}
+	for _, name := range []string{"Pets", "Owners", "Users"} {
+		refs[name], _ = lookup(schemas, name, "items", "$ref").(string)
+	}
+
+	if refs["Pets"] == refs["Owners"] {
+		t.Errorf("expected different components for different files, got %q", refs["Pets"])
+	}
+	if refs["Pets"] != refs["Users"] {
+		t.Errorf("expected the same component for the same file, got %q and %q", refs["Pets"], refs["Users"])
+	}
+	if refs["Pets"] == "#/components/schemas/pet" {
+		t.Error("expected the existing component not to be overwritten")
+	}
+}
+
+func TestBundleRejectsReferencesOutsideOfFileSystem(t *testing.T) {
+	fsys := fstest.MapFS{
+		"openapi.yaml": {Data: []byte(strings.Replace(bundleTestSpec, "./models/pet.yaml", "../pet.yaml", 1))},
+	}
+
+	if _, err := Bundle(fsys, "openapi.yaml", BundleHoist); err == nil || !strings.Contains(err.Error(), "points outside of the file system") {
+		t.Errorf("expected error for reference outside of the file system, got %v", err)
+	}
+}
+
+func TestBundleReturnsSpecWithoutExternalReferencesUnchanged(t *testing.T) {
+	fsys := fstest.MapFS{"openapi.yaml": {Data: []byte(testSpec)}}
+
+	data, err := Bundle(fsys, "openapi.yaml", BundleHoist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != testSpec {
+		t.Errorf("expected the spec to be unchanged, got %s", data)
+	}
+}
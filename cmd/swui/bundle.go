@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+)
+
+func runBundle(arguments []string) error {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	mode := flags.String("mode", "hoist", "How external references are resolved (hoist or inline)")
+	format := flags.String("format", "json", "Output format (json or yaml)")
+	output := flags.String("o", "", "Output file (default: stdout)")
+	flags.Usage = func() {
+		fmt.Println("Usage: swui bundle [flags] <path-to-schema>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(arguments); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one spec file")
+	}
+
+	var bundleMode swaggerui.BundleMode
+	switch *mode {
+	case "hoist":
+		bundleMode = swaggerui.BundleHoist
+	case "inline":
+		bundleMode = swaggerui.BundleInline
+	default:
+		return fmt.Errorf("unknown bundle mode %q", *mode)
+	}
+
+	fileName := flags.Arg(0)
+	spec, err := swaggerui.Bundle(os.DirFS(filepath.Dir(fileName)), filepath.Base(fileName), bundleMode)
+	if err != nil {
+		return err
+	}
+
+	// The spec is returned unchanged if it does not contain external references,
+	// so it may still be YAML and is always re-encoded.
+	var doc any
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return fmt.Errorf("cannot parse bundled spec: %w", err)
+	}
+
+	var data []byte
+	switch *format {
+	case "json":
+		data, err = json.MarshalIndent(jsonCompatible(doc), "", "  ")
+	case "yaml":
+		data, err = yaml.Marshal(doc)
+	default:
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot encode bundled spec: %w", err)
+	}
+
+	if *output == "" {
+		_, err = fmt.Println(string(data))
+		return err
+	}
+
+	return os.WriteFile(*output, data, 0o644)
+}
+
+// jsonCompatible converts YAML mappings with non-string keys (e.g., unquoted status codes)
+// into maps with string keys, so that they can be encoded as JSON.
+func jsonCompatible(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, elem := range value {
+			value[key] = jsonCompatible(elem)
+		}
+		return value
+	case map[any]any:
+		result := make(map[string]any, len(value))
+		for key, elem := range value {
+			result[fmt.Sprint(key)] = jsonCompatible(elem)
+		}
+		return result
+	case []any:
+		for idx, elem := range value {
+			value[idx] = jsonCompatible(elem)
+		}
+		return value
+	default:
+		return v
+	}
+}
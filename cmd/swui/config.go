@@ -0,0 +1,543 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// configFileName is the name of the config file that is discovered in the directory of the first spec.
+const configFileName = ".swui.yaml"
+
+// fileConfig is the content of a config file (YAML or JSON). Settings with a flag tag set the flag
+// of the same name, unless the flag is given on the command line or its environment variable is set.
+// All other settings map onto handler options. Relative paths are resolved relative to the directory
+// of the config file. There are no settings for WithConfigURL and WithMergedSpecs, because the specs
+// that are shown are determined by the spec paths.
+type fileConfig struct {
+	Specs []string `yaml:"specs" path:"true"`
+
+	Addr      *string `yaml:"addr" flag:"addr"`
+	Port      *int    `yaml:"port" flag:"port"`
+	NoBrowser *bool   `yaml:"noBrowser" flag:"no-browser"`
+	TLS       *bool   `yaml:"tls" flag:"tls"`
+	TLSCert   *string `yaml:"tlsCert" flag:"tls-cert" path:"true"`
+	TLSKey    *string `yaml:"tlsKey" flag:"tls-key" path:"true"`
+
+	Title              *string `yaml:"title" flag:"title"`
+	BasePath           *string `yaml:"basePath" flag:"base-path"`
+	DocExpansion       *string `yaml:"docExpansion" flag:"doc-expansion" enum:"list,full,none"`
+	ModelsExpandDepth  *int    `yaml:"defaultModelsExpandDepth" flag:"models-expand-depth"`
+	TryItOutEnabled    *bool   `yaml:"tryItOutEnabled" flag:"try-it-out"`
+	DeepLinking        *bool   `yaml:"deepLinking" flag:"deep-linking"`
+	PersistAuth        *bool   `yaml:"persistAuthorization" flag:"persist-auth"`
+	ShowFilterBar      *bool   `yaml:"showFilterBar" flag:"show-filter-bar"`
+	DisplayOperationID *bool   `yaml:"displayOperationId" flag:"show-operation-id"`
+	Theme              *string `yaml:"theme" flag:"theme" enum:"light,dark"`
+	CSS                *string `yaml:"css" flag:"css" path:"true"`
+	Validator          *string `yaml:"validator" flag:"validator"`
+
+	Timeout  *duration         `yaml:"timeout" flag:"timeout"`
+	Headers  map[string]string `yaml:"headers"`
+	CACert   *string           `yaml:"caCert" flag:"ca-cert" path:"true"`
+	Insecure *bool             `yaml:"insecure" flag:"insecure"`
+	Refetch  *bool             `yaml:"refetch" flag:"refetch"`
+	Interval *duration         `yaml:"interval" flag:"interval"`
+
+	HAR       *string `yaml:"har" flag:"har" path:"true"`
+	HARRedact *string `yaml:"harRedact" flag:"har-redact"`
+
+	CustomCSS               string   `yaml:"customCSS"`
+	Filter                  *string  `yaml:"filter"`
+	DefaultModelExpandDepth *int     `yaml:"defaultModelExpandDepth"`
+	DefaultModelRendering   *string  `yaml:"defaultModelRendering" enum:"example,model"`
+	SupportedSubmitMethods  []string `yaml:"supportedSubmitMethods"`
+	QueryConfigEnabled      *bool    `yaml:"queryConfigEnabled"`
+	ShowExtensions          *bool    `yaml:"showExtensions"`
+	ShowCommonExtensions    *bool    `yaml:"showCommonExtensions"`
+	ShowMutatedRequest      *bool    `yaml:"showMutatedRequest"`
+	DisplayRequestDuration  *bool    `yaml:"displayRequestDuration"`
+	WithCredentials         *bool    `yaml:"withCredentials"`
+	MaxDisplayedTags        *int     `yaml:"maxDisplayedTags"`
+	OAuth2RedirectURL       *string  `yaml:"oauth2RedirectUrl"`
+	Layout                  *string  `yaml:"layout" enum:"BaseLayout,StandaloneLayout"`
+
+	OAuth2             *oauth2Config          `yaml:"oauth2"`
+	SpecValidation     bool                   `yaml:"specValidation"`
+	OpenAPI3Conversion bool                   `yaml:"openAPI3Conversion"`
+	SpecFiles          bool                   `yaml:"specFiles"`
+	BundleMode         *string                `yaml:"bundleMode" enum:"hoist,inline"`
+	Transforms         *transformsConfig      `yaml:"transforms"`
+	TryItOutProxy      *tryItOutProxyConfig   `yaml:"tryItOutProxy"`
+	MockServer         *mockServerConfig      `yaml:"mockServer"`
+	SecurityHeaders    *securityHeadersConfig `yaml:"securityHeaders"`
+	ServerRewrite      *serverRewriteConfig   `yaml:"serverRewrite"`
+	BasicAuth          *basicAuthConfig       `yaml:"basicAuth"`
+	BearerAuth         *bearerAuthConfig      `yaml:"bearerAuth"`
+	Lint               *lintConfig            `yaml:"lint"`
+
+	fileName string
+	// lines contains the line of every key by key path (e.g., "oauth2.clientId").
+	lines map[string]int
+}
+
+// oauth2Config has the fields of swaggerui.OAuth2.
+type oauth2Config struct {
+	ClientID                                  string            `yaml:"clientId"`
+	ClientSecret                              string            `yaml:"clientSecret"`
+	Realm                                     string            `yaml:"realm"`
+	AppName                                   string            `yaml:"appName"`
+	ScopeSeparator                            string            `yaml:"scopeSeparator"`
+	Scopes                                    []string          `yaml:"scopes"`
+	AdditionalQueryStringParams               map[string]string `yaml:"additionalQueryStringParams"`
+	UseBasicAuthenticationWithAccessCodeGrant bool              `yaml:"useBasicAuthenticationWithAccessCodeGrant"`
+	UsePkceWithAuthorizationCodeGrant         bool              `yaml:"usePkceWithAuthorizationCodeGrant"`
+}
+
+type transformsConfig struct {
+	RemoveMarked                []string `yaml:"removeMarked"`
+	RemoveExtensions            []string `yaml:"removeExtensions"`
+	PruneUnreferencedComponents bool     `yaml:"pruneUnreferencedComponents"`
+}
+
+type tryItOutProxyConfig struct {
+	AllowedHosts        []string `yaml:"allowedHosts"`
+	Timeout             duration `yaml:"timeout"`
+	MaxRequestBodySize  int64    `yaml:"maxRequestBodySize"`
+	MaxResponseBodySize int64    `yaml:"maxResponseBodySize"`
+	StripHeaders        []string `yaml:"stripHeaders"`
+}
+
+func (p *tryItOutProxyConfig) settings() swaggerui.TryItOutProxy {
+	return swaggerui.TryItOutProxy{
+		AllowedHosts:        p.AllowedHosts,
+		Timeout:             time.Duration(p.Timeout),
+		MaxRequestBodySize:  p.MaxRequestBodySize,
+		MaxResponseBodySize: p.MaxResponseBodySize,
+		StripHeaders:        p.StripHeaders,
+	}
+}
+
+// mockServerConfig has the fields of swaggerui.MockServer.
+type mockServerConfig struct {
+	StatusHeader      string `yaml:"statusHeader"`
+	ExampleHeader     string `yaml:"exampleHeader"`
+	DisableValidation bool   `yaml:"disableValidation"`
+}
+
+// securityHeadersConfig has the fields of swaggerui.SecurityHeaders.
+type securityHeadersConfig struct {
+	ContentSecurityPolicy bool     `yaml:"contentSecurityPolicy"`
+	ConnectSources        []string `yaml:"connectSources"`
+	ImageSources          []string `yaml:"imageSources"`
+	FrameAncestors        []string `yaml:"frameAncestors"`
+	ReferrerPolicy        string   `yaml:"referrerPolicy"`
+	NoSniff               bool     `yaml:"noSniff"`
+}
+
+// serverRewriteConfig has the fields of swaggerui.ServerRewrite.
+type serverRewriteConfig struct {
+	Mapping               map[string][]string `yaml:"mapping"`
+	UseRequestHost        bool                `yaml:"useRequestHost"`
+	TrustForwardedHeaders bool                `yaml:"trustForwardedHeaders"`
+}
+
+// lintConfig configures the lint command.
+type lintConfig struct {
+	// Rules maps rule names to severities ("error", "warning", "info" or "off").
+	Rules map[string]string `yaml:"rules"`
+}
+
+// basicAuthConfig configures basic authentication with either an htpasswd file or plain text passwords.
+type basicAuthConfig struct {
+	Realm    string `yaml:"realm"`
+	Htpasswd string `yaml:"htpasswd" path:"true"`
+	// Users maps usernames to plain text passwords.
+	Users map[string]string `yaml:"users"`
+}
+
+type bearerAuthConfig struct {
+	Realm  string   `yaml:"realm"`
+	Tokens []string `yaml:"tokens"`
+}
+
+// duration is a time.Duration that is written as string in config files (e.g., "5s").
+type duration time.Duration
+
+func (d *duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q (e.g., \"5s\" or \"1m\")", node.Value)
+	}
+
+	*d = duration(parsed)
+	return nil
+}
+
+func (d duration) String() string {
+	return time.Duration(d).String()
+}
+
+// configError is an error in a config file.
+type configError struct {
+	fileName string
+	line     int
+	key      string
+	msg      string
+}
+
+func (e *configError) Error() string {
+	if e.key == "" {
+		return fmt.Sprintf("%s:%d: %s", e.fileName, e.line, e.msg)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", e.fileName, e.line, e.key, e.msg)
+}
+
+// findConfigFile returns the path of the config file. If no path is given, the config file is
+// discovered in the directory of the first spec path (or the working directory). It returns
+// an empty path if no config file exists.
+func findConfigFile(configPath string, specPaths []string) (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+
+	dir := "."
+	if len(specPaths) > 0 && specPaths[0] != stdinPath && !isURL(specPaths[0]) {
+		dir = specPaths[0]
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+	}
+
+	configPath = filepath.Join(dir, configFileName)
+	if _, err := os.Stat(configPath); errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return configPath, nil
+}
+
+// loadConfigFile reads and validates a config file.
+func loadConfigFile(fileName string) (*fileConfig, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("cannot parse config file %s: %w", fileName, err)
+	}
+
+	cfg := &fileConfig{fileName: fileName, lines: make(map[string]int)}
+	if len(root.Content) == 0 {
+		return cfg, nil
+	}
+
+	d := configDecoder{cfg: cfg, dir: filepath.Dir(fileName)}
+	if err := d.decode(root.Content[0], reflect.ValueOf(cfg).Elem(), ""); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// configDecoder decodes a config file node by node, so that errors point at the offending key.
+type configDecoder struct {
+	cfg *fileConfig
+	// dir is the directory relative paths are resolved against.
+	dir string
+}
+
+var yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+func (d *configDecoder) errorf(node *yaml.Node, key, format string, args ...any) error {
+	return &configError{fileName: d.cfg.fileName, line: node.Line, key: key, msg: fmt.Sprintf(format, args...)}
+}
+
+func (d *configDecoder) decode(node *yaml.Node, v reflect.Value, key string) error {
+	if v.Kind() == reflect.Pointer {
+		if node.Tag == "!!null" {
+			return nil
+		}
+
+		v.Set(reflect.New(v.Type().Elem()))
+		return d.decode(node, v.Elem(), key)
+	}
+
+	if v.Kind() != reflect.Struct || reflect.PointerTo(v.Type()).Implements(yamlUnmarshalerType) {
+		if v.Kind() == reflect.Map && node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				d.cfg.lines[key+"."+node.Content[i].Value] = node.Content[i].Line
+			}
+		}
+
+		if err := node.Decode(v.Addr().Interface()); err != nil {
+			var typeErr *yaml.TypeError
+			if errors.As(err, &typeErr) {
+				return d.errorf(node, key, "invalid value %q: expected %s", node.Value, describeType(v.Type()))
+			}
+			return d.errorf(node, key, "%v", err)
+		}
+		return nil
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return d.errorf(node, key, "expected an object")
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		fieldKey := keyNode.Value
+		if key != "" {
+			fieldKey = key + "." + keyNode.Value
+		}
+		d.cfg.lines[fieldKey] = keyNode.Line
+
+		field, ok := fieldByYAMLName(v.Type(), keyNode.Value)
+		if !ok {
+			return d.errorf(keyNode, fieldKey, "unknown key")
+		}
+
+		value := v.FieldByIndex(field.Index)
+		if err := d.decode(valueNode, value, fieldKey); err != nil {
+			return err
+		}
+
+		if allowed := field.Tag.Get("enum"); allowed != "" && value.Kind() == reflect.Pointer && !value.IsNil() {
+			if values := strings.Split(allowed, ","); !slices.Contains(values, value.Elem().String()) {
+				return d.errorf(valueNode, fieldKey, "invalid value %q: must be one of %s", value.Elem().String(), strings.Join(values, ", "))
+			}
+		}
+
+		if field.Tag.Get("path") == "true" {
+			d.resolvePaths(value)
+		}
+	}
+
+	return nil
+}
+
+// resolvePaths resolves relative paths in a string, string pointer or string slice value.
+func (d *configDecoder) resolvePaths(v reflect.Value) {
+	switch {
+	case v.Kind() == reflect.Pointer && !v.IsNil():
+		d.resolvePaths(v.Elem())
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			d.resolvePaths(v.Index(i))
+		}
+	case v.Kind() == reflect.String:
+		p := v.String()
+		if p != "" && p != stdinPath && !isURL(p) && !filepath.IsAbs(p) {
+			v.SetString(filepath.Join(d.dir, p))
+		}
+	}
+}
+
+func fieldByYAMLName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.IsExported() && strings.Split(field.Tag.Get("yaml"), ",")[0] == name {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		return "a list of " + strings.TrimPrefix(strings.TrimPrefix(describeType(t.Elem()), "a "), "an ") + "s"
+	case reflect.Map:
+		return "an object"
+	default:
+		return t.String()
+	}
+}
+
+// applyFlags sets the flags of all settings with a flag tag, unless the flag is given on the
+// command line or its environment variable is set.
+func (c *fileConfig) applyFlags(flags *flag.FlagSet, env *envDefaults) error {
+	explicit := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := field.Tag.Get("flag")
+		value := v.Field(i)
+
+		// Flags of other commands (e.g., "interval" of the serve command) are not defined.
+		if name == "" || value.IsNil() || explicit[name] || env.isSet(flagEnvName(name)) || flags.Lookup(name) == nil {
+			continue
+		}
+
+		if err := flags.Set(name, fmt.Sprint(value.Elem().Interface())); err != nil {
+			key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			return &configError{fileName: c.fileName, line: c.lines[key], key: key, msg: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// flagEnvName returns the name of the environment variable of a flag without prefix.
+func flagEnvName(flagName string) string {
+	return strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// headers returns the headers as "Name: value" strings sorted by name.
+func (c *fileConfig) headers() []string {
+	var headers []string
+	for _, name := range slices.Sorted(maps.Keys(c.Headers)) {
+		headers = append(headers, name+": "+c.Headers[name])
+	}
+
+	return headers
+}
+
+// bundleMode returns the mode references of spec files to other files are bundled with.
+func (c *fileConfig) bundleMode() swaggerui.BundleMode {
+	if c != nil && c.BundleMode != nil && *c.BundleMode == "inline" {
+		return swaggerui.BundleInline
+	}
+
+	return swaggerui.BundleHoist
+}
+
+// options returns the handler options of all settings that have no flag, except for the
+// "Try it out" proxy (see openArguments.options).
+func (c *fileConfig) options() ([]swaggerui.Option, error) {
+	var opts []swaggerui.Option
+
+	if c.CustomCSS != "" {
+		opts = append(opts, swaggerui.WithCustomCSS(c.CustomCSS))
+	}
+	if c.Filter != nil {
+		opts = append(opts, swaggerui.WithFilter(true, *c.Filter))
+	}
+	if c.DefaultModelExpandDepth != nil {
+		opts = append(opts, swaggerui.WithDefaultModelExpandDepth(*c.DefaultModelExpandDepth))
+	}
+	if c.DefaultModelRendering != nil {
+		opts = append(opts, swaggerui.WithDefaultModelRendering(swaggerui.ModelRendering(*c.DefaultModelRendering)))
+	}
+	if c.SupportedSubmitMethods != nil {
+		opts = append(opts, swaggerui.WithSupportedSubmitMethods(c.SupportedSubmitMethods...))
+	}
+	if c.QueryConfigEnabled != nil {
+		opts = append(opts, swaggerui.WithQueryConfigEnabled(*c.QueryConfigEnabled))
+	}
+	if c.ShowExtensions != nil {
+		opts = append(opts, swaggerui.WithShowExtensions(*c.ShowExtensions))
+	}
+	if c.ShowCommonExtensions != nil {
+		opts = append(opts, swaggerui.WithShowCommonExtensions(*c.ShowCommonExtensions))
+	}
+	if c.ShowMutatedRequest != nil {
+		opts = append(opts, swaggerui.WithShowMutatedRequest(*c.ShowMutatedRequest))
+	}
+	if c.DisplayRequestDuration != nil {
+		opts = append(opts, swaggerui.WithDisplayRequestDuration(*c.DisplayRequestDuration))
+	}
+	if c.WithCredentials != nil {
+		opts = append(opts, swaggerui.WithCredentials(*c.WithCredentials))
+	}
+	if c.MaxDisplayedTags != nil {
+		opts = append(opts, swaggerui.WithMaxDisplayedTags(*c.MaxDisplayedTags))
+	}
+	if c.OAuth2RedirectURL != nil {
+		opts = append(opts, swaggerui.WithOauth2RedirectUrl(*c.OAuth2RedirectURL))
+	}
+	if c.Layout != nil {
+		opts = append(opts, swaggerui.WithLayout(swaggerui.Layout(*c.Layout)))
+	}
+	if c.OAuth2 != nil {
+		opts = append(opts, swaggerui.WithOAuth2(swaggerui.OAuth2(*c.OAuth2)))
+	}
+	if c.SpecValidation {
+		opts = append(opts, swaggerui.WithSpecValidation())
+	}
+	if c.OpenAPI3Conversion {
+		opts = append(opts, swaggerui.WithOpenAPI3Conversion())
+	}
+	if c.SpecFiles {
+		opts = append(opts, swaggerui.WithSpecFiles())
+	}
+	opts = append(opts, swaggerui.WithBundleMode(c.bundleMode()))
+
+	if t := c.Transforms; t != nil {
+		var transforms []swaggerui.SpecTransform
+		for _, extension := range t.RemoveMarked {
+			transforms = append(transforms, swaggerui.RemoveMarked(extension))
+		}
+		if len(t.RemoveExtensions) > 0 {
+			transforms = append(transforms, swaggerui.RemoveExtensions(t.RemoveExtensions...))
+		}
+		if t.PruneUnreferencedComponents {
+			transforms = append(transforms, swaggerui.PruneUnreferencedComponents())
+		}
+		opts = append(opts, swaggerui.WithSpecTransforms(transforms...))
+	}
+
+	if c.MockServer != nil {
+		opts = append(opts, swaggerui.WithMockServer(swaggerui.MockServer(*c.MockServer)))
+	}
+	if c.SecurityHeaders != nil {
+		opts = append(opts, swaggerui.WithSecurityHeaders(swaggerui.SecurityHeaders(*c.SecurityHeaders)))
+	}
+	if c.ServerRewrite != nil {
+		opts = append(opts, swaggerui.WithServerRewrite(swaggerui.ServerRewrite(*c.ServerRewrite)))
+	}
+
+	if c.BasicAuth != nil && c.BearerAuth != nil {
+		return nil, &configError{fileName: c.fileName, line: c.lines["bearerAuth"], key: "bearerAuth", msg: "cannot be combined with basicAuth"}
+	}
+
+	if a := c.BasicAuth; a != nil {
+		switch {
+		case a.Htpasswd != "" && len(a.Users) > 0:
+			return nil, &configError{fileName: c.fileName, line: c.lines["basicAuth.users"], key: "basicAuth.users", msg: "cannot be combined with htpasswd"}
+		case len(a.Users) > 0:
+			opts = append(opts, swaggerui.WithAuthorizer(swaggerui.BasicAuth(a.Realm, a.Users)))
+		default:
+			authorizer, err := swaggerui.BasicAuthFromHtpasswdFile(a.Realm, a.Htpasswd)
+			if err != nil {
+				return nil, &configError{fileName: c.fileName, line: c.lines["basicAuth.htpasswd"], key: "basicAuth.htpasswd", msg: err.Error()}
+			}
+			opts = append(opts, swaggerui.WithAuthorizer(authorizer))
+		}
+	}
+
+	if a := c.BearerAuth; a != nil {
+		if len(a.Tokens) == 0 {
+			return nil, &configError{fileName: c.fileName, line: c.lines["bearerAuth"], key: "bearerAuth.tokens", msg: "at least one token is required"}
+		}
+		opts = append(opts, swaggerui.WithAuthorizer(swaggerui.BearerToken(a.Realm, a.Tokens...)))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigFileOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "layout and bundling",
+			config: "layout: StandaloneLayout\nbundleMode: inline\nspecFiles: true\n",
+		},
+		{
+			name:   "basic authentication with plain text passwords",
+			config: "basicAuth:\n  realm: docs\n  users:\n    admin: secret\n",
+		},
+		{
+			name:   "bearer token authentication",
+			config: "bearerAuth:\n  realm: docs\n  tokens: [secret]\n",
+		},
+		{
+			name:   "invalid layout",
+			config: "layout: TopbarLayout\n",
+			err:    `layout: invalid value "TopbarLayout"`,
+		},
+		{
+			name:   "invalid bundle mode",
+			config: "bundleMode: flat\n",
+			err:    `bundleMode: invalid value "flat"`,
+		},
+		{
+			name:   "htpasswd file and plain text passwords",
+			config: "basicAuth:\n  htpasswd: .htpasswd\n  users:\n    admin: secret\n",
+			err:    "basicAuth.users: cannot be combined with htpasswd",
+		},
+		{
+			name:   "basic and bearer token authentication",
+			config: "basicAuth:\n  users:\n    admin: secret\nbearerAuth:\n  tokens: [secret]\n",
+			err:    "bearerAuth: cannot be combined with basicAuth",
+		},
+		{
+			name:   "bearer token authentication without tokens",
+			config: "bearerAuth:\n  realm: docs\n",
+			err:    "bearerAuth.tokens: at least one token is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), configFileName)
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := loadConfigFile(configPath)
+			if err == nil {
+				_, err = config.options()
+			}
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
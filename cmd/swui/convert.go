@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+func runConvert(arguments []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	format := flags.String("format", "json", "Output format (json or yaml)")
+	output := flags.String("o", "", "Output file (default: stdout)")
+	flags.Usage = func() {
+		fmt.Println("Usage: swui convert [flags] <path-to-swagger-2.0-schema>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(arguments); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one spec file")
+	}
+
+	spec, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("cannot read spec file: %w", err)
+	}
+
+	converted, err := swaggerui.ConvertToOpenAPI3(spec)
+	if err != nil {
+		return fmt.Errorf("cannot convert spec: %w", err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(converted, &doc); err != nil {
+		return fmt.Errorf("cannot parse converted spec: %w", err)
+	}
+
+	var data []byte
+	switch *format {
+	case "json":
+		data, err = json.MarshalIndent(doc, "", "  ")
+	case "yaml":
+		data, err = yaml.Marshal(doc)
+	default:
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot encode converted spec: %w", err)
+	}
+
+	if *output == "" {
+		_, err = fmt.Println(string(data))
+		return err
+	}
+
+	return os.WriteFile(*output, data, 0o644)
+}
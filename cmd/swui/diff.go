@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"os"
+)
+
+// runDiff compares two spec files and prints all changes. It returns the exit code of the program,
+// which is 1 if the new spec contains breaking changes.
+func runDiff(arguments []string) (int, error) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := flags.String("format", "text", "Output format (text, json or markdown)")
+	flags.Usage = func() {
+		fmt.Println("Usage: swui diff [flags] <path-to-old-schema> <path-to-new-schema>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(arguments); err != nil {
+		return 2, err
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2, fmt.Errorf("expected exactly two spec files")
+	}
+
+	specs := make([][]byte, 2)
+	for idx, fileName := range flags.Args() {
+		spec, err := os.ReadFile(fileName)
+		if err != nil {
+			return 2, fmt.Errorf("cannot read spec file: %w", err)
+		}
+		specs[idx] = spec
+	}
+
+	diff, err := swaggerui.DiffSpecs(specs[0], specs[1])
+	if err != nil {
+		return 2, err
+	}
+
+	switch *format {
+	case "text":
+		fmt.Print(diff.Text())
+	case "json":
+		data, err := diff.JSON()
+		if err != nil {
+			return 2, err
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		fmt.Print(diff.Markdown())
+	default:
+		return 2, fmt.Errorf("unknown output format %q", *format)
+	}
+
+	if diff.HasBreakingChanges() {
+		return 1, nil
+	}
+
+	return 0, nil
+}
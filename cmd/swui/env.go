@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envPrefix is the prefix of all environment variables that set flag defaults.
+const envPrefix = "SWUI_"
+
+// envDefaults returns flag defaults from environment variables, so that every flag can also be set
+// with an environment variable (e.g., SWUI_TITLE for -title). Explicit flags take precedence.
+type envDefaults struct {
+	getenv func(string) string
+	// err is the first invalid environment variable value.
+	err error
+}
+
+func (e *envDefaults) string(name, fallback string) string {
+	if value := e.getenv(envPrefix + name); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+func (e *envDefaults) bool(name string, fallback bool) bool {
+	value := e.getenv(envPrefix + name)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		e.fail(name, value, err)
+		return fallback
+	}
+
+	return parsed
+}
+
+func (e *envDefaults) int(name string, fallback int) int {
+	value := e.getenv(envPrefix + name)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		e.fail(name, value, err)
+		return fallback
+	}
+
+	return parsed
+}
+
+func (e *envDefaults) fail(name, value string, err error) {
+	if e.err == nil {
+		e.err = fmt.Errorf("invalid value %q for environment variable %s%s: %w", value, envPrefix, name, err)
+	}
+}
+
+// usage appends the environment variable of a flag to its usage text.
+func usage(text, name string) string {
+	return fmt.Sprintf("%s [$%s%s]", text, envPrefix, name)
+}
+
+func (e *envDefaults) duration(name string, fallback time.Duration) time.Duration {
+	value := e.getenv(envPrefix + name)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		e.fail(name, value, err)
+		return fallback
+	}
+
+	return parsed
+}
+
+// lines returns the non-empty lines of an environment variable (e.g., for flags that can be repeated).
+func (e *envDefaults) lines(name string) []string {
+	var lines []string
+	for _, line := range strings.Split(e.getenv(envPrefix+name), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// isSet reports whether the environment variable of the given name (without prefix) is set.
+func (e *envDefaults) isSet(name string) bool {
+	return e.getenv(envPrefix+name) != ""
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHARRecordingAllowsHostsOfServedSpecs records a "Try it out" request for a spec read from stdin,
+// which is served by specsHandler and therefore not known to the handler.
+func TestHARRecordingAllowsHostsOfServedSpecs(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer upstream.Close()
+
+	spec := "openapi: 3.0.3\ninfo: {title: Pets, version: \"1\"}\nservers: [{url: " + upstream.URL + "}]\npaths: {}\n"
+	specs, err := discoverSpecs([]string{stdinPath}, strings.NewReader(spec), remoteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := openArguments{
+		basePath:     "/",
+		docExpansion: string(swaggerui.DocExpansionList),
+		theme:        "light",
+		validator:    "none",
+		harPath:      filepath.Join(t.TempDir(), "requests.har"),
+	}
+	opts, err := args.options(specs, func() []string { return proxyHosts(specs) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/_proxy?url="+url.QueryEscape(upstream.URL+"/pets"), nil)
+	req.Header.Set("Sec-Fetch-Site", "same-origin")
+	req.Header.Set("X-Try-It-Out-Proxy", "true")
+	swaggerui.NewHandler(opts...).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	har, err := os.ReadFile(args.harPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(har), upstream.URL+"/pets") {
+		t.Errorf("expected the request to be recorded:\n%s", har)
+	}
+}
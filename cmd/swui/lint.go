@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"os"
+)
+
+// runLint checks a spec file against style rules and prints all problems. It returns the exit code
+// of the program, which is 1 if problems with severity error were found.
+func runLint(arguments []string) (int, error) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	format := flags.String("format", "text", "Output format (text, json or sarif)")
+	configPath := flags.String("config", "", "Path of the config file with lint rule settings (default: "+configFileName+" in the directory of the spec)")
+	failOnWarnings := flags.Bool("strict", false, "Exits with code 1 on warnings too")
+	listRules := flags.Bool("list-rules", false, "Lists all rules and their default severities")
+	flags.Usage = func() {
+		fmt.Println("Usage: swui lint [flags] <path-to-schema>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(arguments); err != nil {
+		return 2, err
+	}
+
+	if *listRules {
+		for _, rule := range swaggerui.DefaultLintRules() {
+			fmt.Printf("%-24s %-8s %s\n", rule.Name, rule.Severity, rule.Description)
+		}
+		return 0, nil
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2, fmt.Errorf("expected exactly one spec file")
+	}
+
+	fileName := flags.Arg(0)
+	spec, err := os.ReadFile(fileName)
+	if err != nil {
+		return 2, fmt.Errorf("cannot read spec file: %w", err)
+	}
+
+	config, err := lintConfigOf(*configPath, fileName)
+	if err != nil {
+		return 2, err
+	}
+
+	diagnostics, err := swaggerui.LintSpec(spec, config)
+	if err != nil {
+		return 2, fmt.Errorf("cannot lint spec file: %w", err)
+	}
+
+	switch *format {
+	case "text":
+		for _, d := range diagnostics {
+			fmt.Printf("%s:%d:%d: %s: %s [%s]\n", fileName, d.Line, d.Column, d.Severity, d.Message, d.Rule)
+		}
+		if len(diagnostics) == 0 {
+			fmt.Printf("%s: no problems found\n", fileName)
+		}
+	case "json":
+		if diagnostics == nil {
+			diagnostics = []swaggerui.Diagnostic{}
+		}
+		if err := printJSON(diagnostics); err != nil {
+			return 2, err
+		}
+	case "sarif":
+		if err := printJSON(sarifLog(fileName, swaggerui.DefaultLintRules(), diagnostics)); err != nil {
+			return 2, err
+		}
+	default:
+		return 2, fmt.Errorf("unknown output format %q", *format)
+	}
+
+	for _, d := range diagnostics {
+		if d.Severity == swaggerui.SeverityError || (*failOnWarnings && d.Severity == swaggerui.SeverityWarning) {
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// lintConfigOf returns the lint config of the config file, which is discovered next to the spec
+// file, if no path is given.
+func lintConfigOf(configPath, specPath string) (swaggerui.LintConfig, error) {
+	var config swaggerui.LintConfig
+
+	configPath, err := findConfigFile(configPath, []string{specPath})
+	if err != nil || configPath == "" {
+		return config, err
+	}
+
+	cfg, err := loadConfigFile(configPath)
+	if err != nil || cfg.Lint == nil {
+		return config, err
+	}
+
+	config.Severities = make(map[string]swaggerui.Severity, len(cfg.Lint.Rules))
+	for name, severity := range cfg.Lint.Rules {
+		config.Severities[name] = swaggerui.Severity(severity)
+	}
+
+	var ruleErr *swaggerui.LintConfigError
+	if err := config.Validate(); errors.As(err, &ruleErr) {
+		key := "lint.rules." + ruleErr.Rule
+		return config, &configError{fileName: configPath, line: cfg.lines[key], key: key, msg: ruleErr.Message}
+	}
+
+	return config, nil
+}
+
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// sarifLog returns the diagnostics in the Static Analysis Results Interchange Format (SARIF) 2.1.0,
+// which is supported by code scanning tools (e.g., GitHub code scanning).
+func sarifLog(fileName string, rules []swaggerui.LintRule, diagnostics []swaggerui.Diagnostic) map[string]any {
+	sarifRules := make([]map[string]any, len(rules))
+	for idx, rule := range rules {
+		sarifRules[idx] = map[string]any{
+			"id":                   rule.Name,
+			"shortDescription":     map[string]any{"text": rule.Description},
+			"defaultConfiguration": map[string]any{"level": sarifLevel(rule.Severity)},
+		}
+	}
+
+	results := make([]map[string]any, len(diagnostics))
+	for idx, d := range diagnostics {
+		region := map[string]any{}
+		if d.Line > 0 {
+			region["startLine"] = d.Line
+			region["startColumn"] = d.Column
+		}
+
+		results[idx] = map[string]any{
+			"ruleId":  d.Rule,
+			"level":   sarifLevel(d.Severity),
+			"message": map[string]any{"text": d.Message},
+			"locations": []map[string]any{{
+				"physicalLocation": map[string]any{
+					"artifactLocation": map[string]any{"uri": fileName},
+					"region":           region,
+				},
+				"logicalLocations": []map[string]any{{"fullyQualifiedName": d.Pointer}},
+			}},
+		}
+	}
+
+	return map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []map[string]any{{
+			"tool": map[string]any{
+				"driver": map[string]any{
+					"name":           "swui",
+					"informationUri": "https://github.com/alexliesenfeld/go-swagger-ui",
+					"rules":          sarifRules,
+				},
+			},
+			"results": results,
+		}},
+	}
+}
+
+func sarifLevel(severity swaggerui.Severity) string {
+	switch severity {
+	case swaggerui.SeverityError:
+		return "error"
+	case swaggerui.SeverityWarning:
+		return "warning"
+	case swaggerui.SeverityInfo:
+		return "note"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSarifLog(t *testing.T) {
+	rules := []swaggerui.LintRule{
+		{Name: "operation-id", Description: "Every operation has an operationId.", Severity: swaggerui.SeverityWarning},
+		{Name: "camel-case-properties", Description: "Schema properties are camelCase.", Severity: swaggerui.SeverityOff},
+	}
+	diagnostics := []swaggerui.Diagnostic{
+		{Severity: swaggerui.SeverityError, Rule: "operation-id", Message: "operation has no operationId", Pointer: "/paths/~1pets/get", Line: 5, Column: 5},
+		{Severity: swaggerui.SeverityInfo, Rule: "camel-case-properties", Message: `property "pet_id" is not camelCase`, Pointer: "/components/schemas/Pet"},
+	}
+
+	const expected = `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "results": [
+        {
+          "level": "error",
+          "locations": [
+            {
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "/paths/~1pets/get"
+                }
+              ],
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "openapi.yaml"
+                },
+                "region": {
+                  "startColumn": 5,
+                  "startLine": 5
+                }
+              }
+            }
+          ],
+          "message": {
+            "text": "operation has no operationId"
+          },
+          "ruleId": "operation-id"
+        },
+        {
+          "level": "note",
+          "locations": [
+            {
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "/components/schemas/Pet"
+                }
+              ],
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "openapi.yaml"
+                },
+                "region": {}
+              }
+            }
+          ],
+          "message": {
+            "text": "property \"pet_id\" is not camelCase"
+          },
+          "ruleId": "camel-case-properties"
+        }
+      ],
+      "tool": {
+        "driver": {
+          "informationUri": "https://github.com/alexliesenfeld/go-swagger-ui",
+          "name": "swui",
+          "rules": [
+            {
+              "defaultConfiguration": {
+                "level": "warning"
+              },
+              "id": "operation-id",
+              "shortDescription": {
+                "text": "Every operation has an operationId."
+              }
+            },
+            {
+              "defaultConfiguration": {
+                "level": "none"
+              },
+              "id": "camel-case-properties",
+              "shortDescription": {
+                "text": "Schema properties are camelCase."
+              }
+            }
+          ]
+        }
+      }
+    }
+  ],
+  "version": "2.1.0"
+}`
+
+	actual, err := json.MarshalIndent(sarifLog("openapi.yaml", rules, diagnostics), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(actual) != expected {
+		t.Errorf("expected SARIF log\n%s\ngot\n%s", expected, actual)
+	}
+}
+
+func TestLintConfigOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected map[string]swaggerui.Severity
+		err      string
+	}{
+		{
+			name:     "rule severities",
+			config:   "lint:\n  rules:\n    operation-tags: off\n    https-servers: error\n",
+			expected: map[string]swaggerui.Severity{"operation-tags": swaggerui.SeverityOff, "https-servers": swaggerui.SeverityError},
+		},
+		{
+			name:   "unknown rule",
+			config: "lint:\n  rules:\n    operation-tags: off\n    operation-ids: error\n",
+			err:    configFileName + ":4: lint.rules.operation-ids: unknown rule",
+		},
+		{
+			name:   "invalid severity",
+			config: "lint:\n  rules:\n    https-servers: fatal\n",
+			err:    configFileName + `:3: lint.rules.https-servers: invalid severity "fatal": must be one of error, warning, info, off`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, configFileName), []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := lintConfigOf("", filepath.Join(dir, "openapi.yaml"))
+			if tt.err != "" {
+				if err == nil || err.Error() != filepath.Join(dir, tt.err) {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(config.Severities) != len(tt.expected) {
+				t.Fatalf("expected severities %v, got %v", tt.expected, config.Severities)
+			}
+			for name, severity := range tt.expected {
+				if config.Severities[name] != severity {
+					t.Errorf("expected severity %s of rule %s, got %s", severity, name, config.Severities[name])
+				}
+			}
+		})
+	}
+}
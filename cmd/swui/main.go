@@ -1,101 +1,112 @@
 package main
 
 import (
-	"flag"
 	"fmt"
-	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
 	"log"
-	"net/http"
-	"net/http/httptest"
-	"os/exec"
-	"runtime"
+	"os"
 )
 
-type programArguments struct {
-	specFilePath    string
-	persistAuth     bool
-	enableFilterBar bool
+// command is a subcommand of the CLI. run returns the exit code of the program.
+type command struct {
+	name  string
+	usage string
+	run   func(arguments []string) (int, error)
+}
+
+// defaultCommand is run if the first argument is not a command (e.g., "swui spec.yaml").
+const defaultCommand = "open"
+
+var commands = []command{
+	{"open", "swui [open] [flags] <path-to-schema-or-directory | URL | ->...", runOpen},
+	{"serve", "swui serve [flags] <path-to-directory-or-schema>...", runServe},
+	{"mock", "swui mock [flags] <path-to-schema>", exitCode(runMock)},
+	{"validate", "swui validate [flags] <path-to-schema>", runValidate},
+	{"lint", "swui lint [flags] <path-to-schema>", runLint},
+	{"summary", "swui summary [flags] <path-to-schema>", runSummary},
+	{"bundle", "swui bundle [flags] <path-to-schema>", exitCode(runBundle)},
+	{"convert", "swui convert [flags] <path-to-swagger-2.0-schema>", exitCode(runConvert)},
+	{"diff", "swui diff [flags] <path-to-old-schema> <path-to-new-schema>", runDiff},
 }
 
 func main() {
-	args, err := parseFlags()
+	code, err := runCommand(os.Args[1:])
 	if err != nil {
-		log.Fatalf("failed to parse arguments: %v", err)
+		log.Println(err)
 	}
 
-	if args.specFilePath == "" {
+	os.Exit(code)
+}
+
+// runCommand runs the command selected by the first argument.
+func runCommand(arguments []string) (int, error) {
+	// Without arguments, the specs of a config file in the working directory are opened.
+	if _, err := os.Stat(configFileName); len(arguments) == 0 && err != nil {
 		printUsage()
-		return
+		return 2, nil
 	}
 
-	handler, err := newHandler(args)
-	if err != nil {
-		log.Fatalf("cannot create handler: %v", err)
+	if len(arguments) == 0 {
+		return runDefaultCommand(arguments)
 	}
 
-	srv := httptest.NewServer(handler)
-
-	log.Println("starting Swagger UI server at", srv.URL)
-	log.Println("press Ctrl+C to stop")
+	switch arguments[0] {
+	case "help", "-h", "-help", "--help":
+		printUsage()
+		return 0, nil
+	}
 
-	if err := openBrowser(srv.URL); err != nil {
-		log.Fatalf("failed to open browser: %v", err)
+	cmd, ok := findCommand(arguments[0])
+	if !ok {
+		return runDefaultCommand(arguments)
 	}
 
-	<-make(chan struct{})
+	return run(cmd, arguments[1:])
 }
 
-func parseFlags() (programArguments, error) {
-	flag.Parse()
+func runDefaultCommand(arguments []string) (int, error) {
+	cmd, _ := findCommand(defaultCommand)
+	return run(cmd, arguments)
+}
 
-	if flag.NArg() > 1 {
-		return programArguments{}, fmt.Errorf("too many parameters")
+func run(cmd command, arguments []string) (int, error) {
+	code, err := cmd.run(arguments)
+	if err != nil {
+		return code, fmt.Errorf("swui %s: %w", cmd.name, err)
 	}
 
-	specFilePath := flag.Arg(0)
-	persistAuth := flag.Bool("persist-auth", false, "Enables browser authentication persistence")
-	enableFilterBar := flag.Bool("show-filter-bar", false, "Shows a filter bar in the UI that helps to find API operations")
-
-	return programArguments{
-		specFilePath:    specFilePath,
-		persistAuth:     *persistAuth,
-		enableFilterBar: *enableFilterBar,
-	}, nil
+	return code, nil
 }
 
-func printUsage() {
-	fmt.Println("Usage: swui <path-to-schema>")
-	flag.PrintDefaults()
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+
+	return command{}, false
 }
 
-func newHandler(args programArguments) (http.HandlerFunc, error) {
-	return swaggerui.NewHandler(
-		swaggerui.WithSpecFilePath(args.specFilePath),
-		swaggerui.WithPersistAuthorization(args.persistAuth),
-		swaggerui.WithDisplayRequestDuration(true),
-		swaggerui.WithCredentials(true),
-		swaggerui.WithShowCommonExtensions(true),
-		swaggerui.WithShowExtensions(true),
-		swaggerui.WithShowMutatedRequest(true),
-		swaggerui.WithHTMLTitle(args.specFilePath),
-		swaggerui.WithFilter(args.enableFilterBar, ""),
-	), nil
+// exitCode adapts commands that only fail with an error.
+func exitCode(run func(arguments []string) error) func(arguments []string) (int, error) {
+	return func(arguments []string) (int, error) {
+		if err := run(arguments); err != nil {
+			return 1, err
+		}
+
+		return 0, nil
+	}
 }
 
-func openBrowser(url string) error {
-	var cmd string
-	var args []string
-
-	switch runtime.GOOS {
-	case "windows":
-		cmd = "cmd"
-		args = []string{"/c", "start"}
-	case "darwin":
-		cmd = "open"
-	default: // "linux", "freebsd", "openbsd", "netbsd"
-		cmd = "xdg-open"
+func printUsage() {
+	for idx, cmd := range commands {
+		prefix := "Usage: "
+		if idx > 0 {
+			prefix = "       "
+		}
+		fmt.Println(prefix + cmd.usage)
 	}
 
-	args = append(args, url)
-	return exec.Command(cmd, args...).Start()
+	fmt.Println()
+	fmt.Println("Run \"swui <command> -h\" to list the flags of a command.")
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"log"
+	"net/http"
+	"os"
+)
+
+func runMock(arguments []string) error {
+	flags := flag.NewFlagSet("mock", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "Address the mock server listens on")
+	disableValidation := flags.Bool("no-validation", false, "Disables validating requests against the spec")
+	flags.Usage = func() {
+		fmt.Println("Usage: swui mock [flags] <path-to-schema>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(arguments); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one spec file")
+	}
+
+	spec, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("cannot read spec file: %w", err)
+	}
+
+	handler, err := swaggerui.NewMockHandler(spec, swaggerui.MockServer{DisableValidation: *disableValidation})
+	if err != nil {
+		return fmt.Errorf("cannot create mock server: %w", err)
+	}
+
+	log.Println("starting mock server at http://" + *addr)
+	log.Println("use header X-Mock-Status to select a response status code")
+	log.Println("press Ctrl+C to stop")
+
+	return http.ListenAndServe(*addr, handler)
+}
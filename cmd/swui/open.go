@@ -0,0 +1,427 @@
+package main
+
+import (
+	"crypto/tls"
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed themes/dark.css
+var darkThemeCSS string
+
+// openArguments are the arguments of the open and serve commands.
+type openArguments struct {
+	specPaths          []string
+	interval           time.Duration
+	addr               string
+	port               int
+	noBrowser          bool
+	tls                bool
+	tlsCertPath        string
+	tlsKeyPath         string
+	title              string
+	basePath           string
+	docExpansion       string
+	modelsExpandDepth  int
+	tryItOut           bool
+	deepLinking        bool
+	persistAuth        bool
+	enableFilterBar    bool
+	displayOperationID bool
+	theme              string
+	cssFilePath        string
+	validator          string
+	harPath            string
+	harRedact          string
+	remote             remoteOptions
+	configPath         string
+	// config is the loaded config file or nil.
+	config *fileConfig
+}
+
+// parseOpenArguments parses the arguments of the open or serve command. Flags that are not given
+// fall back to the environment variables returned by getenv and then to the config file.
+func parseOpenArguments(command string, arguments []string, getenv func(string) string) (openArguments, error) {
+	var args openArguments
+	env := envDefaults{getenv: getenv}
+	serveMode := command == "serve"
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.StringVar(&args.configPath, "config", env.string("CONFIG", ""),
+		usage("Path of the config file (default: "+configFileName+" in the directory of the first spec)", "CONFIG"))
+	flags.StringVar(&args.addr, "addr", env.string("ADDR", "localhost"),
+		usage("Host or IP address the server listens on", "ADDR"))
+	flags.IntVar(&args.port, "port", env.int("PORT", 0),
+		usage("Port the server listens on (default: a random free port)", "PORT"))
+	flags.BoolVar(&args.noBrowser, "no-browser", env.bool("NO_BROWSER", serveMode),
+		usage("Does not open Swagger UI in a browser window", "NO_BROWSER"))
+	flags.BoolVar(&args.tls, "tls", env.bool("TLS", false),
+		usage("Serves Swagger UI over HTTPS (with a self-signed certificate, if no certificate is given)", "TLS"))
+	flags.StringVar(&args.tlsCertPath, "tls-cert", env.string("TLS_CERT", ""),
+		usage("Path of the PEM certificate file for HTTPS (implies -tls)", "TLS_CERT"))
+	flags.StringVar(&args.tlsKeyPath, "tls-key", env.string("TLS_KEY", ""),
+		usage("Path of the PEM key file for HTTPS (implies -tls)", "TLS_KEY"))
+	flags.StringVar(&args.title, "title", env.string("TITLE", ""),
+		usage("Title of the HTML page (default: path of the spec file)", "TITLE"))
+	flags.StringVar(&args.basePath, "base-path", env.string("BASE_PATH", "/"),
+		usage("URL path Swagger UI is served on", "BASE_PATH"))
+	flags.StringVar(&args.docExpansion, "doc-expansion", env.string("DOC_EXPANSION", string(swaggerui.DocExpansionList)),
+		usage("Default expansion of operations and tags (list, full or none)", "DOC_EXPANSION"))
+	flags.IntVar(&args.modelsExpandDepth, "models-expand-depth", env.int("MODELS_EXPAND_DEPTH", 1),
+		usage("Default expansion depth of models (-1 hides models)", "MODELS_EXPAND_DEPTH"))
+	flags.BoolVar(&args.tryItOut, "try-it-out", env.bool("TRY_IT_OUT", false),
+		usage("Enables \"Try it out\" for all operations by default", "TRY_IT_OUT"))
+	flags.BoolVar(&args.deepLinking, "deep-linking", env.bool("DEEP_LINKING", true),
+		usage("Enables deep linking to tags and operations", "DEEP_LINKING"))
+	flags.BoolVar(&args.persistAuth, "persist-auth", env.bool("PERSIST_AUTH", false),
+		usage("Enables browser authentication persistence", "PERSIST_AUTH"))
+	flags.BoolVar(&args.enableFilterBar, "show-filter-bar", env.bool("SHOW_FILTER_BAR", false),
+		usage("Shows a filter bar in the UI that helps to find API operations", "SHOW_FILTER_BAR"))
+	flags.BoolVar(&args.displayOperationID, "show-operation-id", env.bool("SHOW_OPERATION_ID", false),
+		usage("Shows operation IDs in the operations list", "SHOW_OPERATION_ID"))
+	flags.StringVar(&args.theme, "theme", env.string("THEME", "light"),
+		usage("Color theme of the UI (light or dark)", "THEME"))
+	flags.StringVar(&args.cssFilePath, "css", env.string("CSS", ""),
+		usage("Path of a CSS file that is added to the UI", "CSS"))
+	flags.StringVar(&args.validator, "validator", env.string("VALIDATOR", "none"),
+		usage("Spec validator: none, local (validates specs locally) or the URL of a validator service", "VALIDATOR"))
+	flags.StringVar(&args.harPath, "har", env.string("HAR", ""),
+		usage("Path of a HAR file \"Try it out\" requests are recorded to (enables the \"Try it out\" proxy)", "HAR"))
+	flags.StringVar(&args.harRedact, "har-redact", env.string("HAR_REDACT", strings.Join(swaggerui.DefaultHARRedactions, ",")),
+		usage("Comma-separated headers and query parameters whose values are redacted in the HAR file", "HAR_REDACT"))
+	flags.DurationVar(&args.remote.timeout, "timeout", env.duration("TIMEOUT", 10*time.Second),
+		usage("Timeout of fetching specs from URLs", "TIMEOUT"))
+	args.remote.headers = headerFlags{}
+	flags.Var(args.remote.headers, "header",
+		usage("Header that is sent when specs are fetched from URLs (\"Name: value\", can be repeated)", "HEADERS"))
+	flags.StringVar(&args.remote.caCertPath, "ca-cert", env.string("CA_CERT", ""),
+		usage("Path of a PEM file with CA certificates that are trusted when specs are fetched from URLs", "CA_CERT"))
+	flags.BoolVar(&args.remote.insecure, "insecure", env.bool("INSECURE", false),
+		usage("Does not verify TLS certificates when specs are fetched from URLs", "INSECURE"))
+	flags.BoolVar(&args.remote.refetch, "refetch", env.bool("REFETCH", false),
+		usage("Fetches specs from URLs again when the browser window is reloaded", "REFETCH"))
+	if serveMode {
+		flags.DurationVar(&args.interval, "interval", env.duration("INTERVAL", 2*time.Second),
+			usage("Interval in which the spec directories are checked for changes", "INTERVAL"))
+	}
+	flags.Usage = func() {
+		if serveMode {
+			fmt.Println("Usage: swui serve [flags] <path-to-directory-or-schema>...")
+		} else {
+			fmt.Println("Usage: swui [open] [flags] <path-to-schema-or-directory | URL | ->...")
+		}
+		flags.PrintDefaults()
+	}
+
+	if env.err != nil {
+		return args, env.err
+	}
+
+	if err := flags.Parse(arguments); err != nil {
+		return args, err
+	}
+
+	configPath, err := findConfigFile(args.configPath, flags.Args())
+	if err != nil {
+		return args, err
+	}
+
+	if configPath != "" {
+		args.configPath = configPath
+		if args.config, err = loadConfigFile(configPath); err != nil {
+			return args, err
+		}
+
+		if err := args.config.applyFlags(flags, &env); err != nil {
+			return args, err
+		}
+	}
+
+	args.specPaths = flags.Args()
+	if len(args.specPaths) == 0 && args.config != nil {
+		args.specPaths = args.config.Specs
+	}
+
+	if len(args.specPaths) == 0 {
+		flags.Usage()
+		return args, fmt.Errorf("expected at least one spec file or directory")
+	}
+
+	// Headers given as flags replace the headers of the environment variable (one header per line),
+	// which replace the headers of the config file.
+	if len(args.remote.headers) == 0 {
+		for _, header := range env.lines("HEADERS") {
+			if err := args.remote.headers.Set(header); err != nil {
+				return args, fmt.Errorf("invalid value for environment variable %sHEADERS: %w", envPrefix, err)
+			}
+		}
+	}
+
+	if len(args.remote.headers) == 0 && args.config != nil {
+		for _, header := range args.config.headers() {
+			if err := args.remote.headers.Set(header); err != nil {
+				return args, &configError{fileName: configPath, line: args.config.lines["headers"], key: "headers", msg: err.Error()}
+			}
+		}
+	}
+
+	if serveMode && args.interval <= 0 {
+		return args, fmt.Errorf("invalid interval %s", args.interval)
+	}
+
+	if args.port < 0 || args.port > 65535 {
+		return args, fmt.Errorf("invalid port %d", args.port)
+	}
+
+	if (args.tlsCertPath == "") != (args.tlsKeyPath == "") {
+		return args, fmt.Errorf("-tls-cert and -tls-key must be given together")
+	}
+	args.tls = args.tls || args.tlsCertPath != ""
+
+	if !strings.HasPrefix(args.basePath, "/") {
+		return args, fmt.Errorf("base path %q must start with a slash", args.basePath)
+	}
+	args.basePath = strings.TrimSuffix(args.basePath, "/") + "/"
+
+	switch swaggerui.DocExpansion(args.docExpansion) {
+	case swaggerui.DocExpansionList, swaggerui.DocExpansionFull, swaggerui.DocExpansionNone:
+	default:
+		return args, fmt.Errorf("unknown doc expansion %q", args.docExpansion)
+	}
+
+	if args.theme != "light" && args.theme != "dark" {
+		return args, fmt.Errorf("unknown theme %q", args.theme)
+	}
+
+	return args, nil
+}
+
+// options returns the handler options for the arguments and the spec files to show.
+// Multiple specs are served by specsHandler and can be selected in the topbar. Without specs,
+// Swagger UI loads the list of specs from configEndpoint. The "Try it out" proxy allows requests
+// to the hosts returned by proxyHosts.
+func (args openArguments) options(specs []specFile, proxyHosts func() []string) ([]swaggerui.Option, error) {
+	opts := []swaggerui.Option{
+		swaggerui.WithBasePath(args.basePath),
+		swaggerui.WithDocExpansion(swaggerui.DocExpansion(args.docExpansion)),
+		swaggerui.WithDefaultModelsExpandDepth(args.modelsExpandDepth),
+		swaggerui.WithTryItOutEnabled(args.tryItOut),
+		swaggerui.WithDeepLinking(args.deepLinking),
+		swaggerui.WithPersistAuthorization(args.persistAuth),
+		swaggerui.WithFilter(args.enableFilterBar, ""),
+		swaggerui.WithDisplayOperation(args.displayOperationID),
+		swaggerui.WithDisplayRequestDuration(true),
+		swaggerui.WithCredentials(true),
+		swaggerui.WithShowCommonExtensions(true),
+		swaggerui.WithShowExtensions(true),
+		swaggerui.WithShowMutatedRequest(true),
+	}
+
+	title := args.title
+	if len(specs) == 1 && title == "" {
+		title = specs[0].displayPath
+	}
+
+	switch {
+	case len(specs) == 0:
+		opts = append(opts,
+			swaggerui.WithConfigURL(args.basePath+configEndpoint),
+			swaggerui.WithLayout(swaggerui.LayoutStandaloneLayout),
+		)
+	case len(specs) == 1 && specs[0].load == nil:
+		opts = append(opts, swaggerui.WithSpecFilePath(specs[0].path))
+	case len(specs) == 1:
+		// Specs from stdin and URLs are served by specsHandler like multiple specs.
+		opts = append(opts, swaggerui.WithSpecURL(specURLs(args.basePath, specs)[0].URL))
+	default:
+		opts = append(opts,
+			swaggerui.WithSpecURLs("", specURLs(args.basePath, specs)),
+			swaggerui.WithLayout(swaggerui.LayoutStandaloneLayout),
+		)
+	}
+
+	if title != "" {
+		opts = append(opts, swaggerui.WithHTMLTitle(title))
+	}
+
+	if args.theme == "dark" {
+		opts = append(opts, swaggerui.WithCustomCSS(darkThemeCSS))
+	}
+
+	if args.cssFilePath != "" {
+		css, err := os.ReadFile(args.cssFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CSS file: %w", err)
+		}
+		opts = append(opts, swaggerui.WithCustomCSS(string(css)))
+	}
+
+	switch args.validator {
+	case "none":
+		// Swagger UI disables validation for the validator URL "none".
+		opts = append(opts, swaggerui.WithValidatorURL(true, "none"))
+	case "local":
+		opts = append(opts, swaggerui.WithLocalValidator(swaggerui.LocalValidator{}))
+	default:
+		opts = append(opts, swaggerui.WithValidatorURL(true, args.validator))
+	}
+
+	// Settings of the config file that have no flag override the defaults above.
+	if args.config != nil {
+		configOpts, err := args.config.options()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, configOpts...)
+	}
+
+	// Recording requires the "Try it out" proxy, which keeps the settings of the config file.
+	// Specs served by specsHandler are not known to the handler, so their hosts are allowed explicitly.
+	hasProxyConfig := args.config != nil && args.config.TryItOutProxy != nil
+	if hasProxyConfig || args.harPath != "" {
+		var proxy swaggerui.TryItOutProxy
+		if hasProxyConfig {
+			proxy = args.config.TryItOutProxy.settings()
+		}
+
+		proxy.AllowedHostsFunc = proxyHosts
+		if args.harPath != "" {
+			proxy.Transport = &swaggerui.HARRecorder{FileName: args.harPath, Redact: splitList(args.harRedact)}
+		}
+		opts = append(opts, swaggerui.WithTryItOutProxy(proxy))
+	}
+
+	return opts, nil
+}
+
+// runOpen serves Swagger UI for spec files and opens it in a browser window.
+func runOpen(arguments []string) (int, error) {
+	args, err := parseOpenArguments("open", arguments, os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0, nil
+	}
+	if err != nil {
+		return 2, err
+	}
+
+	if args.config != nil {
+		log.Println("using config file", args.configPath)
+	}
+
+	specs, err := discoverSpecs(args.specPaths, os.Stdin, args.remote)
+	if err != nil {
+		return 1, err
+	}
+
+	opts, err := args.options(specs, func() []string { return proxyHosts(specs) })
+	if err != nil {
+		return 1, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(args.basePath, swaggerui.NewHandler(opts...))
+	if len(specs) > 1 || specs[0].load != nil {
+		mux.Handle(args.basePath+specsEndpoint, specsHandler(args.basePath, args.config.bundleMode(), func() []specFile { return specs }))
+	}
+	if args.basePath != "/" {
+		mux.Handle("/", http.RedirectHandler(args.basePath, http.StatusFound))
+	}
+
+	for _, spec := range specs {
+		source := spec.path
+		if source == stdinPath {
+			source = "stdin"
+		}
+		log.Printf("serving %q from %s", spec.name, source)
+	}
+
+	if err := args.listenAndServe(mux); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// listenAndServe serves the handler on the address of the arguments and opens Swagger UI in a
+// browser window, unless disabled.
+func (args openArguments) listenAndServe(handler http.Handler) error {
+	listener, err := net.Listen("tcp", net.JoinHostPort(args.addr, strconv.Itoa(args.port)))
+	if err != nil {
+		return fmt.Errorf("cannot listen: %w", err)
+	}
+
+	scheme := "http"
+	if args.tls {
+		listenHost, _, _ := net.SplitHostPort(listener.Addr().String())
+		config, err := tlsConfig(args.tlsCertPath, args.tlsKeyPath, []string{args.addr, listenHost})
+		if err != nil {
+			listener.Close()
+			return err
+		}
+
+		listener = tls.NewListener(listener, config)
+		scheme = "https"
+	}
+
+	uiURL := scheme + "://" + browserHost(listener.Addr()) + args.basePath
+
+	log.Println("starting Swagger UI server at", uiURL)
+	log.Println("press Ctrl+C to stop")
+
+	if !args.noBrowser {
+		if err := openBrowser(uiURL); err != nil {
+			log.Printf("cannot open browser, please open %s manually: %v", uiURL, err)
+		}
+	}
+
+	return serve(listener, handler)
+}
+
+// splitList splits a comma-separated list and removes empty items. The result is not nil.
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func openBrowser(url string) error {
+	var cmd string
+	var args []string
+
+	switch runtime.GOOS {
+	case "windows":
+		cmd = "cmd"
+		args = []string{"/c", "start"}
+	case "darwin":
+		cmd = "open"
+	default: // "linux", "freebsd", "openbsd", "netbsd"
+		cmd = "xdg-open"
+	}
+
+	args = append(args, url)
+	browser := exec.Command(cmd, args...)
+	if err := browser.Start(); err != nil {
+		return err
+	}
+
+	// The process is waited for, so that it does not remain a zombie after it has exited.
+	go browser.Wait()
+	return nil
+}
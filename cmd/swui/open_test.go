@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseOpenArguments(t *testing.T) {
+	// The spec is located in an empty directory, so that no config file is found.
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+
+	tests := []struct {
+		name      string
+		arguments []string
+		env       map[string]string
+		check     func(t *testing.T, args openArguments)
+		err       string
+	}{
+		{
+			name:      "defaults",
+			arguments: []string{specPath},
+			check: func(t *testing.T, args openArguments) {
+				if args.port != 0 || args.theme != "light" || args.basePath != "/" || args.tls {
+					t.Errorf("unexpected defaults %+v", args)
+				}
+			},
+		},
+		{
+			name:      "environment variables",
+			arguments: []string{specPath},
+			env:       map[string]string{"SWUI_PORT": "8080", "SWUI_THEME": "dark", "SWUI_BASE_PATH": "/docs", "SWUI_NO_BROWSER": "true"},
+			check: func(t *testing.T, args openArguments) {
+				if args.port != 8080 || args.theme != "dark" || args.basePath != "/docs/" || !args.noBrowser {
+					t.Errorf("environment variables were not applied: %+v", args)
+				}
+			},
+		},
+		{
+			name:      "flags take precedence over environment variables",
+			arguments: []string{"-port", "9090", "-theme", "light", specPath},
+			env:       map[string]string{"SWUI_PORT": "8080", "SWUI_THEME": "dark"},
+			check: func(t *testing.T, args openArguments) {
+				if args.port != 9090 || args.theme != "light" {
+					t.Errorf("flags did not take precedence: %+v", args)
+				}
+			},
+		},
+		{
+			name:      "TLS certificate implies TLS",
+			arguments: []string{"-tls-cert", "cert.pem", "-tls-key", "key.pem", specPath},
+			check: func(t *testing.T, args openArguments) {
+				if !args.tls {
+					t.Error("expected TLS to be enabled")
+				}
+			},
+		},
+		{
+			name:      "help",
+			arguments: []string{"-h"},
+			err:       flag.ErrHelp.Error(),
+		},
+		{
+			name:      "missing spec",
+			arguments: []string{},
+			err:       "expected at least one spec file or directory",
+		},
+		{
+			name:      "invalid port",
+			arguments: []string{"-port", "70000", specPath},
+			err:       "invalid port 70000",
+		},
+		{
+			name:      "invalid port environment variable",
+			arguments: []string{specPath},
+			env:       map[string]string{"SWUI_PORT": "http"},
+			err:       `invalid value "http" for environment variable SWUI_PORT`,
+		},
+		{
+			name:      "invalid doc expansion",
+			arguments: []string{"-doc-expansion", "all", specPath},
+			err:       `unknown doc expansion "all"`,
+		},
+		{
+			name:      "invalid theme",
+			arguments: []string{specPath},
+			env:       map[string]string{"SWUI_THEME": "blue"},
+			err:       `unknown theme "blue"`,
+		},
+		{
+			name:      "TLS certificate without key",
+			arguments: []string{"-tls-cert", "cert.pem", specPath},
+			err:       "-tls-cert and -tls-key must be given together",
+		},
+		{
+			name:      "invalid base path",
+			arguments: []string{"-base-path", "docs", specPath},
+			err:       `base path "docs" must start with a slash`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(name string) string { return tt.env[name] }
+
+			args, err := parseOpenArguments("open", tt.arguments, getenv)
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, args)
+		})
+	}
+}
+
+func TestParseServeArguments(t *testing.T) {
+	dir := t.TempDir()
+
+	args, err := parseOpenArguments("serve", []string{dir}, func(string) string { return "" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !args.noBrowser {
+		t.Error("expected serve mode not to open a browser by default")
+	}
+
+	_, err = parseOpenArguments("serve", []string{"-interval", "0s", dir}, func(string) string { return "" })
+	if err == nil {
+		t.Error("expected an error for an invalid interval")
+	}
+
+	_, err = parseOpenArguments("serve", []string{"-h"}, func(string) string { return "" })
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("expected flag.ErrHelp, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// stdinPath is the path that reads a spec from stdin.
+const stdinPath = "-"
+
+// headerFlags are HTTP headers given as repeated "-header 'Name: value'" flags.
+type headerFlags http.Header
+
+func (h headerFlags) String() string {
+	var headers []string
+	for name, values := range h {
+		for _, value := range values {
+			headers = append(headers, name+": "+value)
+		}
+	}
+
+	return strings.Join(headers, ", ")
+}
+
+func (h headerFlags) Set(value string) error {
+	name, headerValue, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header %q must have the format \"Name: value\"", value)
+	}
+
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(headerValue))
+	return nil
+}
+
+// remoteOptions configure how specs are fetched from URLs.
+type remoteOptions struct {
+	timeout    time.Duration
+	headers    headerFlags
+	caCertPath string
+	insecure   bool
+	// refetch fetches remote specs again whenever they are loaded by the browser.
+	refetch bool
+}
+
+func isURL(p string) bool {
+	return strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://")
+}
+
+// client returns the HTTP client that fetches remote specs.
+func (o remoteOptions) client() (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: o.insecure}
+
+	if o.caCertPath != "" {
+		pem, err := os.ReadFile(o.caCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA certificate: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", o.caCertPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport, Timeout: o.timeout}, nil
+}
+
+// fetchSpec fetches a spec from a URL.
+func fetchSpec(client *http.Client, specURL string, headers http.Header) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch spec: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch spec from %s: unexpected status code %d", specURL, resp.StatusCode)
+	}
+
+	return readSpec(resp.Body)
+}
+
+// readSpec reads a spec of at most maxSpecSize bytes.
+func readSpec(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSpecSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read spec: %w", err)
+	}
+
+	if len(data) > maxSpecSize {
+		return nil, fmt.Errorf("spec is larger than %d bytes", maxSpecSize)
+	}
+
+	return data, nil
+}
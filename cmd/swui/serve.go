@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// uiPath is the path Swagger UI is served on relative to the base path in serve mode.
+	// The base path shows the landing page.
+	uiPath = "ui/"
+
+	// configEndpoint is the path of the Swagger UI config document with the list of specs in serve mode.
+	configEndpoint = "_config.json"
+
+	// apisEndpoint is the path of the JSON list of all specs in serve mode.
+	apisEndpoint = "apis.json"
+)
+
+var landingPageTemplate = template.Must(template.New("landing").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <title>{{ .Title }}</title>
+  <style>
+    body { font-family: sans-serif; margin: 2em auto; max-width: 960px; color: #3b4151; }
+    table { border-collapse: collapse; width: 100%; }
+    th, td { text-align: left; padding: .5em; border-bottom: 1px solid #ddd; }
+    a { color: #4990e2; }
+    .path { font-family: monospace; color: #777; }
+  </style>
+</head>
+<body>
+  <h1>{{ .Title }}</h1>
+  {{ if .APIs }}
+  <table>
+    <tr><th>API</th><th>File</th><th>Spec</th></tr>
+    {{ range .APIs }}
+    <tr>
+      <td><a href="{{ .DocsURL }}">{{ .Name }}</a></td>
+      <td class="path">{{ .Path }}</td>
+      <td><a href="{{ .SpecURL }}">download</a></td>
+    </tr>
+    {{ end }}
+  </table>
+  {{ else }}
+  <p>No OpenAPI or Swagger documents found. New spec files are shown when the page is reloaded.</p>
+  {{ end }}
+</body>
+</html>
+`))
+
+// apiEntry describes a spec on the landing page and in the JSON list of specs.
+type apiEntry struct {
+	Name string `json:"name"`
+	// Path is the path of the spec file relative to the watched directory.
+	Path    string `json:"path"`
+	DocsURL string `json:"docsUrl"`
+	SpecURL string `json:"specUrl"`
+}
+
+// catalogFile is a checked file of a specCatalog.
+type catalogFile struct {
+	modTime time.Time
+	size    int64
+	title   string
+	isSpec  bool
+	hosts   []string
+}
+
+// specCatalog keeps track of the spec documents in a set of directories and spec files. Files are
+// only read again when their modification time or size changes.
+type specCatalog struct {
+	paths []string
+
+	mu    sync.RWMutex
+	specs []specFile
+	files map[string]catalogFile
+}
+
+// newSpecCatalog returns a catalog of the specs in the given paths. Paths that do not exist (yet) are
+// checked again on every refresh.
+func newSpecCatalog(paths []string) (*specCatalog, error) {
+	for _, p := range paths {
+		if p == stdinPath || isURL(p) {
+			return nil, fmt.Errorf("%s: only directories and files can be served", p)
+		}
+	}
+
+	c := &specCatalog{paths: paths}
+	if _, _, err := c.refresh(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// list returns the current specs.
+func (c *specCatalog) list() []specFile {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.specs
+}
+
+// refresh searches the paths for specs again and returns the specs that were added and removed.
+func (c *specCatalog) refresh() ([]specFile, []specFile, error) {
+	c.mu.RLock()
+	cached := c.files
+	c.mu.RUnlock()
+
+	var specs []specFile
+	files := make(map[string]catalogFile)
+
+	check := func(idx int, filePath, displayPath string, info fs.FileInfo) error {
+		file, ok := cached[filePath]
+		if !ok || !file.modTime.Equal(info.ModTime()) || file.size != info.Size() {
+			data, err := os.ReadFile(filePath)
+			if errors.Is(err, fs.ErrNotExist) {
+				// The file was removed while the directory was searched.
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			file = catalogFile{modTime: info.ModTime(), size: info.Size()}
+			file.title, file.isSpec = sniffSpec(data)
+			if file.isSpec {
+				file.hosts = specHosts(data)
+			}
+		}
+
+		files[filePath] = file
+		if file.isSpec {
+			displayPath = filepath.ToSlash(displayPath)
+			specs = append(specs, specFile{
+				id:          strconv.Itoa(idx) + "/" + displayPath,
+				path:        filePath,
+				name:        file.title,
+				displayPath: displayPath,
+				hosts:       file.hosts,
+			})
+		}
+		return nil
+	}
+
+	for idx, p := range c.paths {
+		info, err := os.Stat(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !info.IsDir() {
+			err = check(idx, p, filepath.Base(p), info)
+		} else {
+			dir := p
+			err = walkSpecFiles(dir, func(filePath string, info fs.FileInfo) error {
+				displayPath, _ := filepath.Rel(dir, filePath)
+				return check(idx, filePath, displayPath, info)
+			})
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	nameSpecs(specs)
+
+	c.mu.Lock()
+	previous := c.specs
+	c.specs, c.files = specs, files
+	c.mu.Unlock()
+
+	return specDifference(specs, previous), specDifference(previous, specs), nil
+}
+
+// specDifference returns the specs of a that are not in b.
+func specDifference(a, b []specFile) []specFile {
+	ids := make(map[string]bool, len(b))
+	for _, spec := range b {
+		ids[spec.id] = true
+	}
+
+	var specs []specFile
+	for _, spec := range a {
+		if !ids[spec.id] {
+			specs = append(specs, spec)
+		}
+	}
+
+	return specs
+}
+
+// watch refreshes the catalog in the given interval and logs added and removed specs until the context
+// is done.
+func (c *specCatalog) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		added, removed, err := c.refresh()
+		if err != nil {
+			log.Printf("cannot search for specs: %v", err)
+			continue
+		}
+
+		for _, spec := range added {
+			log.Printf("added %q from %s", spec.name, spec.path)
+		}
+		for _, spec := range removed {
+			log.Printf("removed %q from %s", spec.name, spec.path)
+		}
+	}
+}
+
+// apis returns the current specs with the URLs of Swagger UI and the spec documents.
+func (c *specCatalog) apis(basePath string) []apiEntry {
+	specs := c.list()
+	urls := specURLs(basePath+uiPath, specs)
+
+	apis := make([]apiEntry, len(specs))
+	for idx, spec := range specs {
+		apis[idx] = apiEntry{
+			Name:    spec.name,
+			Path:    spec.displayPath,
+			DocsURL: basePath + uiPath + "?urls.primaryName=" + url.QueryEscape(spec.name),
+			SpecURL: urls[idx].URL,
+		}
+	}
+
+	return apis
+}
+
+// landingPageHandler serves a page that lists all specs with links to Swagger UI.
+func (c *specCatalog) landingPageHandler(basePath, title string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != basePath {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+
+		err := landingPageTemplate.Execute(w, struct {
+			Title string
+			APIs  []apiEntry
+		}{title, c.apis(basePath)})
+		if err != nil {
+			log.Printf("cannot render landing page: %v", err)
+		}
+	})
+}
+
+// apisHandler serves the list of all specs as JSON.
+func (c *specCatalog) apisHandler(basePath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sendJSON(w, c.apis(basePath))
+	})
+}
+
+// configHandler serves the Swagger UI config document with the current specs for the topbar selector.
+// Swagger UI loads it on every page load, so added and removed specs are shown after a reload.
+func (c *specCatalog) configHandler(uiBasePath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sendJSON(w, map[string]any{"urls": specURLs(uiBasePath, c.list())})
+	})
+}
+
+func sendJSON(w http.ResponseWriter, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(data)
+}
+
+// runServe serves Swagger UI for all specs in a set of directories, which are watched for added and
+// removed spec files, and a landing page that lists all specs.
+func runServe(arguments []string) (int, error) {
+	args, err := parseOpenArguments("serve", arguments, os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0, nil
+	}
+	if err != nil {
+		return 2, err
+	}
+
+	if args.config != nil {
+		log.Println("using config file", args.configPath)
+	}
+
+	catalog, err := newSpecCatalog(args.specPaths)
+	if err != nil {
+		return 1, err
+	}
+
+	uiArgs := args
+	uiArgs.basePath = args.basePath + uiPath
+	opts, err := uiArgs.options(nil, func() []string { return proxyHosts(catalog.list()) })
+	if err != nil {
+		return 1, err
+	}
+
+	title := args.title
+	if title == "" {
+		title = "APIs"
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(args.basePath, catalog.landingPageHandler(args.basePath, title))
+	mux.Handle(args.basePath+apisEndpoint, catalog.apisHandler(args.basePath))
+	mux.Handle(uiArgs.basePath, swaggerui.NewHandler(opts...))
+	mux.Handle(uiArgs.basePath+configEndpoint, catalog.configHandler(uiArgs.basePath))
+	mux.Handle(uiArgs.basePath+specsEndpoint, specsHandler(uiArgs.basePath, args.config.bundleMode(), catalog.list))
+	if args.basePath != "/" {
+		mux.Handle("/", http.RedirectHandler(args.basePath, http.StatusFound))
+	}
+
+	for _, spec := range catalog.list() {
+		log.Printf("serving %q from %s", spec.name, spec.path)
+	}
+	log.Printf("checking %s for changes every %s", strings.Join(args.specPaths, ", "), args.interval)
+
+	// The catalog is watched until the server has shut down.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go catalog.watch(ctx, args.interval)
+
+	if err := args.listenAndServe(mux); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const catalogTestSpec = "openapi: 3.0.3\ninfo: {title: Pets, version: \"1\"}\npaths: {}\n"
+
+func TestSpecCatalogRefresh(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "pets", "openapi.yaml")
+
+	catalog, err := newSpecCatalog([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apis := func() []apiEntry {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		catalog.apisHandler("/").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+apisEndpoint, nil))
+
+		var apis []apiEntry
+		if err := json.Unmarshal(rec.Body.Bytes(), &apis); err != nil {
+			t.Fatalf("cannot parse %s: %v", rec.Body, err)
+		}
+		return apis
+	}
+
+	if apis := apis(); len(apis) != 0 {
+		t.Fatalf("expected no specs, got %v", apis)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(specPath), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(specPath, []byte(catalogTestSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Pets\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	added, removed, err := catalog.refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0].path != specPath || len(removed) != 0 {
+		t.Fatalf("expected %s to be added, got added %v and removed %v", specPath, added, removed)
+	}
+
+	expected := apiEntry{Name: "Pets", Path: "pets/openapi.yaml", DocsURL: "/ui/?urls.primaryName=Pets", SpecURL: "/ui/_specs/0/pets/openapi.yaml"}
+	if apis := apis(); len(apis) != 1 || apis[0] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, apis)
+	}
+
+	rec := httptest.NewRecorder()
+	catalog.configHandler("/ui/").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ui/"+configEndpoint, nil))
+	if body := rec.Body.String(); body != `{"urls":[{"name":"Pets","url":"/ui/_specs/0/pets/openapi.yaml"}]}` {
+		t.Errorf("unexpected config %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	catalog.landingPageHandler("/", "APIs").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "Pets") || !strings.Contains(body, "pets/openapi.yaml") {
+		t.Errorf("expected the landing page to list the spec, got %s", body)
+	}
+
+	if err := os.Remove(specPath); err != nil {
+		t.Fatal(err)
+	}
+
+	added, removed, err = catalog.refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 0 || len(removed) != 1 || removed[0].path != specPath {
+		t.Fatalf("expected %s to be removed, got added %v and removed %v", specPath, added, removed)
+	}
+	if apis := apis(); len(apis) != 0 {
+		t.Errorf("expected no specs, got %v", apis)
+	}
+}
+
+func TestSpecCatalogWatchStopsWithContext(t *testing.T) {
+	dir := t.TempDir()
+
+	catalog, err := newSpecCatalog([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		catalog.watch(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	if err := os.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte(catalogTestSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(catalog.list()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the watched catalog to find the spec")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected watch to return when the context is canceled")
+	}
+}
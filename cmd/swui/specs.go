@@ -0,0 +1,355 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// specsEndpoint is the path the spec files are served on when multiple specs are opened.
+	specsEndpoint = "_specs/"
+
+	// maxSpecSize is the maximum size of spec documents that are read from stdin or URLs and of
+	// files that are checked for spec documents when a directory is searched.
+	maxSpecSize = 10 << 20
+)
+
+// skippedDirs are directories that are not searched for spec files.
+var skippedDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// specFile is a spec document that was found on the file system, read from stdin or fetched from a URL.
+type specFile struct {
+	// id identifies the spec in the URL it is served on by specsHandler.
+	id   string
+	path string
+	// name is the name of the spec in the topbar selector.
+	name string
+	// displayPath is the path relative to the searched directory.
+	displayPath string
+	// load returns the content of specs that are not read from the file system.
+	load func() ([]byte, error)
+	// hosts are the hosts of the servers of the spec, which the "Try it out" proxy allows.
+	hosts []string
+}
+
+// fileName returns the file name the spec is served with.
+func (s specFile) fileName() string {
+	if s.path == stdinPath {
+		return "stdin"
+	}
+
+	if isURL(s.path) {
+		if u, err := url.Parse(s.path); err == nil && strings.Trim(u.Path, "/") != "" {
+			return path.Base(u.Path)
+		}
+		return "spec"
+	}
+
+	return filepath.Base(s.path)
+}
+
+// read returns the current content of the spec. References of spec files to other files are bundled
+// with the given mode.
+func (s specFile) read(mode swaggerui.BundleMode) ([]byte, error) {
+	if s.load != nil {
+		return s.load()
+	}
+
+	return swaggerui.Bundle(os.DirFS(filepath.Dir(s.path)), filepath.Base(s.path), mode)
+}
+
+// discoverSpecs returns all specs in the given paths. Directories are searched recursively for files
+// that contain OpenAPI or Swagger documents, regardless of their file extension. The path "-" reads
+// a spec from stdin and URLs are fetched with the remote options. Specs are named by their title.
+// Specs with the same title are distinguished by their path.
+func discoverSpecs(paths []string, stdin io.Reader, remote remoteOptions) ([]specFile, error) {
+	var specs []specFile
+	var client *http.Client
+	var readStdin bool
+
+	add := func(spec specFile, data []byte, title string) {
+		spec.name = title
+		spec.hosts = specHosts(data)
+		spec.displayPath = filepath.ToSlash(spec.displayPath)
+		spec.id = strconv.Itoa(len(specs)) + "/" + spec.fileName()
+		specs = append(specs, spec)
+	}
+
+	for _, p := range paths {
+		switch {
+		case p == stdinPath:
+			if readStdin {
+				return nil, fmt.Errorf("stdin can only be read once")
+			}
+			readStdin = true
+
+			data, err := readSpec(stdin)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read spec from stdin: %w", err)
+			}
+
+			title, ok := sniffSpec(data)
+			if !ok {
+				return nil, fmt.Errorf("stdin does not contain an OpenAPI or Swagger document")
+			}
+
+			add(specFile{path: p, displayPath: "stdin", load: func() ([]byte, error) { return data, nil }}, data, title)
+			continue
+		case isURL(p):
+			if client == nil {
+				var err error
+				if client, err = remote.client(); err != nil {
+					return nil, err
+				}
+			}
+
+			data, err := fetchSpec(client, p, http.Header(remote.headers))
+			if err != nil {
+				return nil, err
+			}
+
+			title, ok := sniffSpec(data)
+			if !ok {
+				return nil, fmt.Errorf("%s is not an OpenAPI or Swagger document", p)
+			}
+
+			specURL, specClient := p, client
+			load := func() ([]byte, error) { return data, nil }
+			if remote.refetch {
+				load = func() ([]byte, error) { return fetchSpec(specClient, specURL, http.Header(remote.headers)) }
+			}
+
+			add(specFile{path: p, displayPath: p, load: load}, data, title)
+			continue
+		}
+
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+
+			title, ok := sniffSpec(data)
+			if !ok {
+				return nil, fmt.Errorf("%s is not an OpenAPI or Swagger document", p)
+			}
+			add(specFile{path: p, displayPath: p}, data, title)
+			continue
+		}
+
+		err = walkSpecFiles(p, func(filePath string, _ fs.FileInfo) error {
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			if title, ok := sniffSpec(data); ok {
+				displayPath, _ := filepath.Rel(p, filePath)
+				add(specFile{path: filePath, displayPath: displayPath}, data, title)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no OpenAPI or Swagger documents found in %s", strings.Join(paths, ", "))
+	}
+
+	nameSpecs(specs)
+
+	return specs, nil
+}
+
+// specHosts returns the hosts of the servers and OAuth2 token URLs of a spec document.
+func specHosts(data []byte) []string {
+	hosts, err := swaggerui.SpecHosts(data)
+	if err != nil {
+		return nil
+	}
+
+	return hosts
+}
+
+// proxyHosts returns the hosts of all specs. The "Try it out" proxy only knows the hosts of a spec
+// that is set with WithSpecFilePath, so the hosts of specs served by specsHandler are added.
+func proxyHosts(specs []specFile) []string {
+	var hosts []string
+	for _, spec := range specs {
+		hosts = append(hosts, spec.hosts...)
+	}
+
+	return hosts
+}
+
+// walkSpecFiles calls fn for every file in the directory that may contain a spec document.
+// Hidden directories, skippedDirs and files larger than maxSpecSize are skipped.
+func walkSpecFiles(dir string, fn func(filePath string, info fs.FileInfo) error) error {
+	return filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if filePath != dir && (strings.HasPrefix(entry.Name(), ".") || skippedDirs[entry.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.Size() > maxSpecSize {
+			return err
+		}
+
+		return fn(filePath, info)
+	})
+}
+
+// nameSpecs sets the names of specs without title to their file name and distinguishes specs
+// with the same title by their path.
+func nameSpecs(specs []specFile) {
+	titles := make(map[string]int)
+	for idx := range specs {
+		if specs[idx].name == "" {
+			specs[idx].name = specs[idx].fileName()
+		}
+		titles[specs[idx].name]++
+	}
+
+	for idx := range specs {
+		if titles[specs[idx].name] > 1 {
+			specs[idx].name += " (" + specs[idx].displayPath + ")"
+		}
+	}
+}
+
+// sniffSpec reports whether data is an OpenAPI or Swagger document and returns its title.
+// Files referenced by specs (e.g., schema files) are not documents on their own.
+func sniffSpec(data []byte) (string, bool) {
+	// Binary files and files without the version field are skipped before they are parsed.
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	if bytes.IndexByte(head, 0) >= 0 || (!bytes.Contains(data, []byte("openapi")) && !bytes.Contains(data, []byte("swagger"))) {
+		return "", false
+	}
+
+	var doc struct {
+		OpenAPI any `yaml:"openapi"`
+		Swagger any `yaml:"swagger"`
+		Info    struct {
+			Title string `yaml:"title"`
+		} `yaml:"info"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", false
+	}
+
+	// The version is a string, but is also accepted if it is not quoted (e.g., "swagger: 2.0").
+	if !isVersion(doc.OpenAPI) && !isVersion(doc.Swagger) {
+		return "", false
+	}
+
+	return strings.TrimSpace(doc.Info.Title), true
+}
+
+func isVersion(v any) bool {
+	switch v.(type) {
+	case string, int, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// specURLs returns the URLs the spec files are served on by specsHandler.
+func specURLs(basePath string, specs []specFile) []swaggerui.SpecURL {
+	urls := make([]swaggerui.SpecURL, len(specs))
+	for idx, spec := range specs {
+		urls[idx] = swaggerui.SpecURL{
+			Name: spec.name,
+			URL:  basePath + specsEndpoint + escapePath(spec.id),
+		}
+	}
+
+	return urls
+}
+
+// escapePath escapes every segment of a slash-separated path.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for idx, segment := range segments {
+		segments[idx] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// specsHandler serves the specs returned by specs on "_specs/<id>" (e.g., "_specs/0/openapi.yaml").
+// Spec files are read on every request, so that changes are shown when the browser window is reloaded.
+// References to other files are bundled into the served document with the given mode.
+func specsHandler(basePath string, mode swaggerui.BundleMode, specs func() []specFile) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, basePath+specsEndpoint)
+
+		var spec specFile
+		var found bool
+		for _, s := range specs() {
+			if s.id == id {
+				spec, found = s, true
+				break
+			}
+		}
+
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := spec.read(mode)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if isURL(spec.path) {
+				status = http.StatusBadGateway
+			}
+
+			log.Printf("cannot load spec %s: %v", spec.path, err)
+			http.Error(w, fmt.Sprintf("cannot load spec: %v", err), status)
+			return
+		}
+
+		contentType := "application/yaml"
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			contentType = "application/json"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(data)
+	})
+}
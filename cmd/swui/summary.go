@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"os"
+)
+
+// runSummary prints an overview of the tags, operations, security requirements and servers of a spec.
+func runSummary(arguments []string) (int, error) {
+	flags := flag.NewFlagSet("summary", flag.ExitOnError)
+	format := flags.String("format", "table", "Output format (table, json or markdown)")
+	tagList := flags.String("tag", "", "Only shows operations with one of these tags (comma-separated)")
+	flags.Usage = func() {
+		fmt.Println("Usage: swui summary [flags] <path-to-schema>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(arguments); err != nil {
+		return 2, err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2, fmt.Errorf("expected exactly one spec file")
+	}
+
+	spec, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return 2, fmt.Errorf("cannot read spec file: %w", err)
+	}
+
+	summary, err := swaggerui.SummarizeSpec(spec, splitList(*tagList)...)
+	if err != nil {
+		return 2, err
+	}
+
+	switch *format {
+	case "table":
+		fmt.Print(summary.Table())
+	case "json":
+		data, err := summary.JSON()
+		if err != nil {
+			return 2, err
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		fmt.Print(summary.Markdown())
+	default:
+		return 2, fmt.Errorf("unknown output format %q", *format)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"time"
+)
+
+// selfSignedValidity is the validity of generated self-signed certificates.
+const selfSignedValidity = 30 * 24 * time.Hour
+
+// tlsConfig returns the TLS configuration of the server. If no certificate and key files are given,
+// a self-signed certificate for localhost and the given hosts is generated in memory.
+func tlsConfig(certFile, keyFile string, hosts []string) (*tls.Config, error) {
+	var cert tls.Certificate
+	var err error
+
+	if certFile != "" {
+		cert, err = tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load TLS certificate: %w", err)
+		}
+	} else {
+		cert, err = selfSignedCertificate(hosts)
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate TLS certificate: %w", err)
+		}
+
+		fingerprint := sha256.Sum256(cert.Certificate[0])
+		log.Println("generated self-signed certificate with SHA-256 fingerprint", hex.EncodeToString(fingerprint[:]))
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// selfSignedCertificate generates a self-signed certificate for localhost, the loopback
+// addresses and the given hosts (host names or IP addresses).
+func selfSignedCertificate(hosts []string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{Organization: []string{"swui"}, CommonName: "localhost"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	for _, host := range hosts {
+		ip := net.ParseIP(host)
+		switch {
+		case ip == nil && host != "" && host != "localhost":
+			template.DNSNames = append(template.DNSNames, host)
+		case ip != nil && !ip.IsUnspecified() && !containsIP(template.IPAddresses, ip):
+			template.IPAddresses = append(template.IPAddresses, ip)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, other := range ips {
+		if other.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
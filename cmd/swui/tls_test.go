@@ -0,0 +1,58 @@
+package main
+
+import (
+	"crypto/x509"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSelfSignedCertificate(t *testing.T) {
+	cert, err := selfSignedCertificate([]string{"", "0.0.0.0", "::", "localhost", "127.0.0.1", "docs.example.com", "192.168.1.10", "fe80::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"localhost", "docs.example.com"}; !reflect.DeepEqual(parsed.DNSNames, expected) {
+		t.Errorf("expected DNS names %v, got %v", expected, parsed.DNSNames)
+	}
+
+	expectedIPs := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback, net.ParseIP("192.168.1.10"), net.ParseIP("fe80::1")}
+	if len(parsed.IPAddresses) != len(expectedIPs) {
+		t.Fatalf("expected IP addresses %v, got %v", expectedIPs, parsed.IPAddresses)
+	}
+	for idx, ip := range expectedIPs {
+		if !parsed.IPAddresses[idx].Equal(ip) {
+			t.Errorf("expected IP addresses %v, got %v", expectedIPs, parsed.IPAddresses)
+			break
+		}
+	}
+
+	for _, host := range []string{"localhost", "docs.example.com", "127.0.0.1", "::1", "192.168.1.10"} {
+		if err := parsed.VerifyHostname(host); err != nil {
+			t.Errorf("expected the certificate to be valid for %s: %v", host, err)
+		}
+	}
+	if err := parsed.VerifyHostname("other.example.com"); err == nil {
+		t.Error("expected the certificate not to be valid for other.example.com")
+	}
+
+	if validity := parsed.NotAfter.Sub(time.Now()); validity <= 0 || validity > selfSignedValidity {
+		t.Errorf("expected the certificate to be valid for %s, got %s", selfSignedValidity, validity)
+	}
+	if parsed.ExtKeyUsage[0] != x509.ExtKeyUsageServerAuth {
+		t.Errorf("expected a server certificate, got key usages %v", parsed.ExtKeyUsage)
+	}
+}
+
+func TestTLSConfigRejectsMissingCertificateFiles(t *testing.T) {
+	if _, err := tlsConfig("missing.crt", "missing.key", nil); err == nil {
+		t.Error("expected an error for missing certificate files")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	swaggerui "github.com/alexliesenfeld/go-swagger-ui"
+	"os"
+)
+
+// runValidate validates a spec file and prints all diagnostics. It returns the exit code of the program,
+// which is 1 if the spec contains errors.
+func runValidate(arguments []string) (int, error) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	format := flags.String("format", "text", "Output format (text or json)")
+	failOnWarnings := flags.Bool("strict", false, "Exits with code 1 on warnings too")
+	flags.Usage = func() {
+		fmt.Println("Usage: swui validate [flags] <path-to-schema>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(arguments); err != nil {
+		return 2, err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2, fmt.Errorf("expected exactly one spec file")
+	}
+
+	fileName := flags.Arg(0)
+	spec, err := os.ReadFile(fileName)
+	if err != nil {
+		return 2, fmt.Errorf("cannot read spec file: %w", err)
+	}
+
+	diagnostics, err := swaggerui.ValidateSpec(spec)
+	if err != nil {
+		return 2, fmt.Errorf("cannot parse spec file: %w", err)
+	}
+
+	switch *format {
+	case "text":
+		for _, d := range diagnostics {
+			fmt.Printf("%s:%d:%d: %s: %s [%s]\n", fileName, d.Line, d.Column, d.Severity, d.Message, d.Rule)
+		}
+		if len(diagnostics) == 0 {
+			fmt.Printf("%s: no problems found\n", fileName)
+		}
+	case "json":
+		if diagnostics == nil {
+			diagnostics = []swaggerui.Diagnostic{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diagnostics); err != nil {
+			return 2, err
+		}
+	default:
+		return 2, fmt.Errorf("unknown output format %q", *format)
+	}
+
+	for _, d := range diagnostics {
+		if d.Severity == swaggerui.SeverityError || (*failOnWarnings && d.Severity == swaggerui.SeverityWarning) {
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -1,6 +1,11 @@
 package go_swagger_ui
 
-import "strings"
+import (
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync"
+)
 
 type configValue[T any] struct {
 	IsSet bool
@@ -9,10 +14,17 @@ type configValue[T any] struct {
 
 type uiConfig struct {
 	htmlTitle                string
+	customCSS                string
 	basePath                 string
 	spec                     []byte
+	specMu                   sync.RWMutex
 	configURL                configValue[string]
 	specFilePath             string
+	specFS                   fs.FS
+	mergeSources             []MergeSource
+	mergeOptions             MergeOptions
+	specFSName               string
+	bundleMode               BundleMode
 	url                      configValue[string]
 	urls                     []SpecURL
 	urlsPrimary              configValue[string]
@@ -35,8 +47,39 @@ type uiConfig struct {
 	persistAuthorization     configValue[bool]
 	withCredentials          configValue[bool]
 	oauth2RedirectUrl        configValue[string]
+	oauth2                   configValue[OAuth2]
 	maxDisplayedTags         configValue[int]
 	validatorUrl             configValue[string]
+	securityHeaders          configValue[SecurityHeaders]
+	authorizer               Authorizer
+	specFilter               SpecFilter
+	specFilterCache          specFilterCache
+	openAPI3Conversion       bool
+	specTransforms           []SpecTransform
+	serverRewrite            configValue[ServerRewrite]
+	tryItOutProxy            configValue[TryItOutProxy]
+	mockServer               configValue[MockServer]
+	specValidation           bool
+	localValidator           configValue[LocalValidator]
+	specFiles                bool
+	endpoints                map[string]http.Handler
+}
+
+// currentSpec returns the prepared spec. Use it instead of reading the spec field while requests
+// are served, because a spec file set with WithSpecFilePath is reloaded concurrently.
+func (cfg *uiConfig) currentSpec() []byte {
+	cfg.specMu.RLock()
+	defer cfg.specMu.RUnlock()
+
+	return cfg.spec
+}
+
+// setSpec replaces the prepared spec while requests are served.
+func (cfg *uiConfig) setSpec(spec []byte) {
+	cfg.specMu.Lock()
+	defer cfg.specMu.Unlock()
+
+	cfg.spec = spec
 }
 
 type DocExpansion string
@@ -261,6 +304,47 @@ func WithOauth2RedirectUrl(oauth2RedirectUrl string) Option {
 	}
 }
 
+// OAuth2 contains the settings that Swagger UI uses for OAuth2 authorization. See WithOAuth2.
+type OAuth2 struct {
+	// ClientID is the default client ID.
+	ClientID string `json:"clientId,omitempty"`
+
+	// ClientSecret is the default client secret. Never use this setting in production, because
+	// the secret is visible to everyone who can open Swagger UI.
+	ClientSecret string `json:"clientSecret,omitempty"`
+
+	// Realm is added to the authorization URL and token URL as realm query parameter.
+	Realm string `json:"realm,omitempty"`
+
+	// AppName is the application name that is displayed in the authorization popup.
+	AppName string `json:"appName,omitempty"`
+
+	// ScopeSeparator is the separator of scopes in the scope query parameter. Default is " ".
+	ScopeSeparator string `json:"scopeSeparator,omitempty"`
+
+	// Scopes are the initially selected scopes.
+	Scopes []string `json:"scopes,omitempty"`
+
+	// AdditionalQueryStringParams are added to the authorization URL and token URL.
+	AdditionalQueryStringParams map[string]string `json:"additionalQueryStringParams,omitempty"`
+
+	// UseBasicAuthenticationWithAccessCodeGrant sends the client ID and secret with HTTP Basic
+	// authentication in access code grant flows instead of in the request body.
+	UseBasicAuthenticationWithAccessCodeGrant bool `json:"useBasicAuthenticationWithAccessCodeGrant,omitempty"`
+
+	// UsePkceWithAuthorizationCodeGrant enables PKCE (Proof Key for Code Exchange) for
+	// authorization code grant flows.
+	UsePkceWithAuthorizationCodeGrant bool `json:"usePkceWithAuthorizationCodeGrant,omitempty"`
+}
+
+// WithOAuth2 sets the default settings of the OAuth2 authorization popup of Swagger UI.
+// Refer to https://github.com/swagger-api/swagger-ui/blob/master/docs/usage/oauth2.md for more information.
+func WithOAuth2(oauth2 OAuth2) Option {
+	return func(cfg *uiConfig) {
+		cfg.oauth2 = configValue[OAuth2]{Value: oauth2, IsSet: true}
+	}
+}
+
 // WithHTMLTitle sets the index HTML page htmlTitle.
 func WithHTMLTitle(title string) Option {
 	return func(cfg *uiConfig) {
@@ -268,6 +352,15 @@ func WithHTMLTitle(title string) Option {
 	}
 }
 
+// WithCustomCSS adds a style sheet to the index HTML page after the Swagger UI style sheets,
+// so that it can override their rules (e.g., to apply a company theme or a dark mode).
+// Calling it multiple times appends the style sheets in order.
+func WithCustomCSS(css string) Option {
+	return func(cfg *uiConfig) {
+		cfg.customCSS += css + "\n"
+	}
+}
+
 // WithLayout sets the name of a component available via the plugin system to use as the top-level
 // layout for Swagger UI.
 // Possible values are "BaseLayout" and "StandaloneLayout".
@@ -14,10 +14,36 @@ func yamlOrJSONToJSON(data []byte) ([]byte, error) {
 		}
 	}
 
-	newV, err := json.Marshal(vMap)
+	newV, err := json.Marshal(normalizeYAMLValue(vMap))
 	if err != nil {
 		return nil, fmt.Errorf("cannot convert value to JSON: %w", err)
 	}
 
 	return newV, nil
 }
+
+// normalizeYAMLValue converts all mappings with non-string keys (e.g., unquoted
+// response status codes like 200) into mappings with string keys, so that they
+// can be marshalled as JSON.
+func normalizeYAMLValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, elem := range value {
+			value[key] = normalizeYAMLValue(elem)
+		}
+		return value
+	case map[any]any:
+		result := make(map[string]any, len(value))
+		for key, elem := range value {
+			result[fmt.Sprint(key)] = normalizeYAMLValue(elem)
+		}
+		return result
+	case []any:
+		for idx, elem := range value {
+			value[idx] = normalizeYAMLValue(elem)
+		}
+		return value
+	default:
+		return v
+	}
+}
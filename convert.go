@@ -0,0 +1,656 @@
+package go_swagger_ui
+
+import (
+	"slices"
+	"strings"
+)
+
+// WithOpenAPI3Conversion converts Swagger 2.0 specs to OpenAPI 3.0 (see ConvertToOpenAPI3)
+// before they are transformed, so that all specs are rendered the same way. Specs that
+// are not Swagger 2.0 documents are not changed.
+func WithOpenAPI3Conversion() Option {
+	return func(cfg *uiConfig) {
+		cfg.openAPI3Conversion = true
+	}
+}
+
+// ConvertToOpenAPI3 converts a Swagger 2.0 document (as YAML or JSON) to an OpenAPI 3.0 JSON document.
+// The host, basePath and schemes are converted to servers, definitions, parameters, responses and
+// securityDefinitions are moved to components, and body and formData parameters are converted to
+// request bodies using the media types of consumes. Responses use the media types of produces.
+// Documents that are not Swagger 2.0 documents are returned as JSON without changes.
+func ConvertToOpenAPI3(spec []byte) ([]byte, error) {
+	data, err := yamlOrJSONToJSON(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertSpec(data)
+}
+
+// convertSpec converts a Swagger 2.0 JSON document to OpenAPI 3.0.
+func convertSpec(data []byte) ([]byte, error) {
+	spec, err := parseSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSwagger2(spec) {
+		return data, nil
+	}
+
+	c := &converter{
+		spec:     spec,
+		consumes: stringList(spec["consumes"], "application/json"),
+		produces: stringList(spec["produces"], "application/json"),
+	}
+
+	return marshalSpec(c.convert())
+}
+
+type converter struct {
+	spec     map[string]any
+	consumes []string
+	produces []string
+}
+
+func (c *converter) convert() map[string]any {
+	result := map[string]any{"openapi": "3.0.3"}
+	components := make(map[string]any)
+
+	for key, value := range c.spec {
+		switch key {
+		case "swagger", "host", "basePath", "schemes", "consumes", "produces", "definitions",
+			"parameters", "responses", "securityDefinitions", "paths":
+		default:
+			result[key] = value // info, tags, security, externalDocs and extensions
+		}
+	}
+
+	result["servers"] = c.servers()
+
+	if definitions, ok := c.spec["definitions"].(map[string]any); ok {
+		schemas := make(map[string]any, len(definitions))
+		for name, schema := range definitions {
+			schemas[name] = convertSchema(schema)
+		}
+		components["schemas"] = schemas
+	}
+
+	if parameters, ok := c.spec["parameters"].(map[string]any); ok {
+		params := make(map[string]any)
+		requestBodies := make(map[string]any)
+
+		for name, param := range parameters {
+			paramObj, _ := param.(map[string]any)
+			switch paramObj["in"] {
+			case "body":
+				requestBodies[name] = c.requestBody([]map[string]any{paramObj}, c.consumes)
+			case "formData":
+				// Form parameters cannot be components in OpenAPI 3, so they are inlined into operations.
+			default:
+				params[name] = convertParameter(paramObj)
+			}
+		}
+
+		if len(params) > 0 {
+			components["parameters"] = params
+		}
+		if len(requestBodies) > 0 {
+			components["requestBodies"] = requestBodies
+		}
+	}
+
+	if responses, ok := c.spec["responses"].(map[string]any); ok {
+		converted := make(map[string]any, len(responses))
+		for name, response := range responses {
+			converted[name] = c.response(response, c.produces)
+		}
+		components["responses"] = converted
+	}
+
+	if definitions, ok := c.spec["securityDefinitions"].(map[string]any); ok {
+		schemes := make(map[string]any, len(definitions))
+		for name, definition := range definitions {
+			definitionObj, _ := definition.(map[string]any)
+			schemes[name] = convertSecurityScheme(definitionObj)
+		}
+		components["securitySchemes"] = schemes
+	}
+
+	if len(components) > 0 {
+		result["components"] = components
+	}
+
+	if paths, ok := c.spec["paths"].(map[string]any); ok {
+		converted := make(map[string]any, len(paths))
+		for path, pathItem := range paths {
+			pathItemObj, ok := pathItem.(map[string]any)
+			if !ok {
+				converted[path] = pathItem
+				continue
+			}
+			converted[path] = c.pathItem(pathItemObj)
+		}
+		result["paths"] = converted
+	} else {
+		result["paths"] = map[string]any{}
+	}
+
+	return rewriteSwagger2Refs(result)
+}
+
+// servers converts the host, basePath and schemes of the spec to servers.
+func (c *converter) servers() []any {
+	host, _ := c.spec["host"].(string)
+	basePath, _ := c.spec["basePath"].(string)
+	if basePath == "" {
+		basePath = "/"
+	}
+
+	if host == "" {
+		return []any{map[string]any{"url": basePath}}
+	}
+
+	schemes := stringList(c.spec["schemes"], "")
+	servers := make([]any, 0, len(schemes))
+	for _, scheme := range schemes {
+		prefix := "//" // protocol-relative, if no scheme has been specified
+		if scheme != "" {
+			prefix = scheme + "://"
+		}
+		servers = append(servers, map[string]any{"url": prefix + host + strings.TrimSuffix(basePath, "/")})
+	}
+
+	return servers
+}
+
+func (c *converter) pathItem(pathItem map[string]any) map[string]any {
+	result := make(map[string]any, len(pathItem))
+
+	// Body and form parameters of the path item apply to all of its operations.
+	pathParams, pathBodyParams := c.splitParameters(pathItem["parameters"])
+
+	for key, value := range pathItem {
+		switch {
+		case key == "parameters":
+			if len(pathParams) > 0 {
+				result[key] = pathParams
+			}
+		case slices.Contains(httpMethods, key):
+			operation, ok := value.(map[string]any)
+			if !ok {
+				result[key] = value
+				continue
+			}
+			result[key] = c.operation(operation, pathBodyParams)
+		default:
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+func (c *converter) operation(operation map[string]any, pathBodyParams []map[string]any) map[string]any {
+	result := make(map[string]any, len(operation))
+
+	consumes := stringList(operation["consumes"], c.consumes...)
+	produces := stringList(operation["produces"], c.produces...)
+
+	params, bodyParams := c.splitParameters(operation["parameters"])
+	bodyParams = c.mergeBodyParameters(pathBodyParams, bodyParams)
+
+	for key, value := range operation {
+		switch key {
+		case "consumes", "produces", "schemes":
+		case "parameters":
+			if len(params) > 0 {
+				result[key] = params
+			}
+		case "responses":
+			responses, _ := value.(map[string]any)
+			converted := make(map[string]any, len(responses))
+			for code, response := range responses {
+				if strings.HasPrefix(code, "x-") {
+					converted[code] = response
+					continue
+				}
+				converted[code] = c.response(response, produces)
+			}
+			result[key] = converted
+		default:
+			result[key] = value
+		}
+	}
+
+	if len(bodyParams) > 0 {
+		result["requestBody"] = c.requestBody(bodyParams, consumes)
+	}
+
+	return result
+}
+
+// splitParameters converts all parameters except body and form parameters, which are returned
+// separately, because they are converted into a request body.
+func (c *converter) splitParameters(value any) ([]any, []map[string]any) {
+	list, _ := value.([]any)
+
+	var params []any
+	var bodyParams []map[string]any
+	for _, param := range list {
+		paramObj, _ := param.(map[string]any)
+
+		switch c.resolveParameter(paramObj)["in"] {
+		case "body", "formData":
+			// References to body parameters are kept, so that they can point to request bodies.
+			bodyParams = append(bodyParams, paramObj)
+		default:
+			if _, isRef := paramObj["$ref"]; isRef {
+				params = append(params, paramObj)
+			} else {
+				params = append(params, convertParameter(paramObj))
+			}
+		}
+	}
+
+	return params, bodyParams
+}
+
+// resolveParameter returns the parameter a parameter reference points to or the parameter itself.
+func (c *converter) resolveParameter(param map[string]any) map[string]any {
+	ref, ok := param["$ref"].(string)
+	if !ok {
+		return param
+	}
+
+	target, _ := resolveRef(c.spec, ref)
+	resolved, _ := target.(map[string]any)
+
+	return resolved
+}
+
+// mergeBodyParameters returns the body and form parameters of an operation, including
+// the ones of its path item that are not overridden by the operation.
+func (c *converter) mergeBodyParameters(pathParams, opParams []map[string]any) []map[string]any {
+	if len(pathParams) == 0 {
+		return opParams
+	}
+
+	hasBody := false
+	names := make(map[string]struct{})
+	for _, param := range opParams {
+		resolved := c.resolveParameter(param)
+		if resolved["in"] == "body" {
+			hasBody = true
+		} else if name, ok := resolved["name"].(string); ok {
+			names[name] = struct{}{}
+		}
+	}
+
+	merged := opParams
+	for _, param := range pathParams {
+		resolved := c.resolveParameter(param)
+		isBody := resolved["in"] == "body"
+		name, hasName := resolved["name"].(string)
+		_, overridden := names[name]
+
+		// Form parameters without a name are invalid and left out of the request body.
+		if (isBody && hasBody) || (!isBody && (!hasName || overridden)) {
+			continue
+		}
+
+		merged = append(merged, param)
+	}
+
+	return merged
+}
+
+// requestBody converts body and form parameters into a request body.
+func (c *converter) requestBody(params []map[string]any, consumes []string) any {
+	// A reference to a single body parameter is converted into a reference to a request body.
+	if len(params) == 1 {
+		if ref, ok := params[0]["$ref"].(string); ok {
+			if target, _ := resolveRef(c.spec, ref); lookup(target, "in") == "body" {
+				return map[string]any{"$ref": strings.Replace(ref, "#/parameters/", "#/components/requestBodies/", 1)}
+			}
+		}
+	}
+
+	formSchema := map[string]any{"type": "object", "properties": map[string]any{}}
+	var bodySchema any
+	var required []any
+	body := make(map[string]any)
+	hasFile := false
+
+	for _, param := range params {
+		param = c.resolveParameter(param)
+
+		if param["in"] == "body" {
+			bodySchema = convertSchema(param["schema"])
+			if description, ok := param["description"]; ok {
+				body["description"] = description
+			}
+			if isRequired, _ := param["required"].(bool); isRequired {
+				body["required"] = true
+			}
+			for key, value := range param {
+				if strings.HasPrefix(key, "x-") {
+					body[key] = value
+				}
+			}
+			continue
+		}
+
+		name, ok := param["name"].(string)
+		if !ok {
+			continue
+		}
+
+		property := parameterSchema(param)
+		if description, ok := param["description"]; ok {
+			property["description"] = description
+		}
+		if param["type"] == "file" {
+			hasFile = true
+		}
+		formSchema["properties"].(map[string]any)[name] = property
+
+		if isRequired, _ := param["required"].(bool); isRequired {
+			required = append(required, name)
+			body["required"] = true
+		}
+	}
+
+	schema := bodySchema
+	if schema == nil {
+		if len(required) > 0 {
+			formSchema["required"] = required
+		}
+		schema = formSchema
+
+		// Form parameters can only be sent with form media types.
+		var formTypes []string
+		for _, mediaType := range consumes {
+			if mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data" {
+				formTypes = append(formTypes, mediaType)
+			}
+		}
+		switch {
+		case len(formTypes) > 0:
+			consumes = formTypes
+		case hasFile:
+			consumes = []string{"multipart/form-data"}
+		default:
+			consumes = []string{"application/x-www-form-urlencoded"}
+		}
+	}
+
+	content := make(map[string]any, len(consumes))
+	for _, mediaType := range consumes {
+		content[mediaType] = map[string]any{"schema": schema}
+	}
+	body["content"] = content
+
+	return body
+}
+
+func (c *converter) response(response any, produces []string) any {
+	responseObj, ok := response.(map[string]any)
+	if !ok {
+		return response
+	}
+
+	if _, isRef := responseObj["$ref"]; isRef {
+		return responseObj
+	}
+
+	result := make(map[string]any, len(responseObj))
+	for key, value := range responseObj {
+		switch key {
+		case "schema", "examples":
+		case "headers":
+			headers, _ := value.(map[string]any)
+			converted := make(map[string]any, len(headers))
+			for name, header := range headers {
+				headerObj, _ := header.(map[string]any)
+				convertedHeader := map[string]any{"schema": parameterSchema(headerObj)}
+				if description, ok := headerObj["description"]; ok {
+					convertedHeader["description"] = description
+				}
+				converted[name] = convertedHeader
+			}
+			result[key] = converted
+		default:
+			result[key] = value
+		}
+	}
+
+	if _, ok := result["description"]; !ok {
+		result["description"] = ""
+	}
+
+	examples, _ := responseObj["examples"].(map[string]any)
+	schema, hasSchema := responseObj["schema"]
+	if !hasSchema && len(examples) == 0 {
+		return result
+	}
+
+	content := make(map[string]any)
+	for _, mediaType := range produces {
+		mediaTypeObj := make(map[string]any)
+		if hasSchema {
+			mediaTypeObj["schema"] = convertSchema(schema)
+		}
+		if example, ok := examples[mediaType]; ok {
+			mediaTypeObj["example"] = example
+		}
+		content[mediaType] = mediaTypeObj
+	}
+
+	// Examples for media types that are not listed in produces are kept as well.
+	for mediaType, example := range examples {
+		if _, ok := content[mediaType]; !ok {
+			mediaTypeObj := map[string]any{"example": example}
+			if hasSchema {
+				mediaTypeObj["schema"] = convertSchema(schema)
+			}
+			content[mediaType] = mediaTypeObj
+		}
+	}
+
+	result["content"] = content
+
+	return result
+}
+
+// schemaFields are the fields of non-body parameters, headers and items that
+// describe the schema of the value.
+var schemaFields = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+// convertParameter converts a non-body parameter.
+func convertParameter(param map[string]any) map[string]any {
+	if _, isRef := param["$ref"]; isRef {
+		return param
+	}
+
+	result := make(map[string]any, len(param))
+	for key, value := range param {
+		if !slices.Contains(schemaFields, key) && key != "collectionFormat" && key != "x-example" {
+			result[key] = value
+		}
+	}
+
+	result["schema"] = parameterSchema(param)
+
+	if example, ok := param["x-example"]; ok {
+		result["example"] = example
+	}
+
+	switch param["collectionFormat"] {
+	case "csv":
+		if param["in"] == "query" || param["in"] == "cookie" {
+			result["style"], result["explode"] = "form", false
+		} else {
+			result["style"] = "simple"
+		}
+	case "multi":
+		result["style"], result["explode"] = "form", true
+	case "ssv":
+		result["style"] = "spaceDelimited"
+	case "pipes":
+		result["style"] = "pipeDelimited"
+	}
+
+	return result
+}
+
+// parameterSchema returns the schema of a non-body parameter, header or items object.
+func parameterSchema(param map[string]any) map[string]any {
+	schema := make(map[string]any)
+	for _, key := range schemaFields {
+		if value, ok := param[key]; ok {
+			schema[key] = value
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		schema["items"] = parameterSchema(items)
+	}
+
+	if schema["type"] == "file" {
+		schema["type"], schema["format"] = "string", "binary"
+	}
+
+	return schema
+}
+
+// convertSchema converts the Swagger 2.0 specific parts of a schema and its sub-schemas.
+func convertSchema(schema any) any {
+	s, ok := schema.(map[string]any)
+	if !ok {
+		return schema
+	}
+
+	result := make(map[string]any, len(s))
+	for key, value := range s {
+		switch key {
+		case "properties":
+			properties, _ := value.(map[string]any)
+			converted := make(map[string]any, len(properties))
+			for name, property := range properties {
+				converted[name] = convertSchema(property)
+			}
+			result[key] = converted
+		case "items", "additionalProperties", "not":
+			result[key] = convertSchema(value)
+		case "allOf", "anyOf", "oneOf":
+			subSchemas, _ := value.([]any)
+			converted := make([]any, len(subSchemas))
+			for idx, subSchema := range subSchemas {
+				converted[idx] = convertSchema(subSchema)
+			}
+			result[key] = converted
+		case "x-nullable":
+			result["nullable"] = value
+		case "discriminator":
+			if propertyName, ok := value.(string); ok {
+				result[key] = map[string]any{"propertyName": propertyName}
+			} else {
+				result[key] = value
+			}
+		default:
+			result[key] = value
+		}
+	}
+
+	if result["type"] == "file" {
+		result["type"], result["format"] = "string", "binary"
+	}
+
+	return result
+}
+
+func convertSecurityScheme(definition map[string]any) map[string]any {
+	result := make(map[string]any)
+	for key, value := range definition {
+		if key == "description" || strings.HasPrefix(key, "x-") {
+			result[key] = value
+		}
+	}
+
+	switch definition["type"] {
+	case "basic":
+		result["type"], result["scheme"] = "http", "basic"
+	case "apiKey":
+		result["type"], result["name"], result["in"] = "apiKey", definition["name"], definition["in"]
+	case "oauth2":
+		flow := map[string]any{"scopes": map[string]any{}}
+		if scopes, ok := definition["scopes"]; ok {
+			flow["scopes"] = scopes
+		}
+
+		flowName := map[any]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}[definition["flow"]]
+
+		if flowName == "implicit" || flowName == "authorizationCode" {
+			flow["authorizationUrl"] = definition["authorizationUrl"]
+		}
+		if flowName != "implicit" {
+			flow["tokenUrl"] = definition["tokenUrl"]
+		}
+
+		result["type"] = "oauth2"
+		result["flows"] = map[string]any{flowName: flow}
+	default:
+		result["type"] = definition["type"]
+	}
+
+	return result
+}
+
+// swagger2RefPrefixes maps Swagger 2.0 reference prefixes to OpenAPI 3.0 reference prefixes.
+var swagger2RefPrefixes = [][2]string{
+	{"#/definitions/", "#/components/schemas/"},
+	{"#/parameters/", "#/components/parameters/"},
+	{"#/responses/", "#/components/responses/"},
+}
+
+// rewriteSwagger2Refs replaces all references to Swagger 2.0 components.
+func rewriteSwagger2Refs(spec map[string]any) map[string]any {
+	rewriteRefs(spec, func(ref string) string {
+		for _, prefixes := range swagger2RefPrefixes {
+			if strings.HasPrefix(ref, prefixes[0]) {
+				return prefixes[1] + strings.TrimPrefix(ref, prefixes[0])
+			}
+		}
+		return ref
+	})
+
+	return spec
+}
+
+// stringList returns the strings of a JSON array or the default values if the value is not a non-empty array.
+func stringList(v any, defaults ...string) []string {
+	list, _ := v.([]any)
+
+	var values []string
+	for _, elem := range list {
+		if s, ok := elem.(string); ok {
+			values = append(values, s)
+		}
+	}
+
+	if len(values) == 0 {
+		return defaults
+	}
+
+	return values
+}
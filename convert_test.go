@@ -0,0 +1,237 @@
+package go_swagger_ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertToOpenAPI3(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected string
+	}{
+		{
+			name: "body parameter, servers and security definitions",
+			spec: `
+swagger: "2.0"
+info: {title: Pets, version: "1"}
+host: api.example.com
+basePath: /v1/
+schemes: [https, http]
+consumes: [application/json, application/xml]
+produces: [application/json]
+security: [{apiKey: []}]
+securityDefinitions:
+  basic: {type: basic, description: Basic authentication}
+  apiKey: {type: apiKey, name: X-API-Key, in: header}
+  implicit: {type: oauth2, flow: implicit, authorizationUrl: "https://auth.example.com/authorize", scopes: {read: Read}}
+  password: {type: oauth2, flow: password, tokenUrl: "https://auth.example.com/token", scopes: {}}
+  application: {type: oauth2, flow: application, tokenUrl: "https://auth.example.com/token"}
+  accessCode:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: "https://auth.example.com/authorize"
+    tokenUrl: "https://auth.example.com/token"
+    scopes: {write: Write}
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+      tag: {type: string, x-nullable: true}
+paths:
+  /pets:
+    post:
+      parameters:
+        - {name: pet, in: body, required: true, description: The pet, x-codegen: pet, schema: {$ref: "#/definitions/Pet"}}
+        - {name: tags, in: query, type: array, items: {type: string}, collectionFormat: multi}
+      responses:
+        "201":
+          description: created
+          schema: {$ref: "#/definitions/Pet"}
+          headers: {Location: {type: string, description: URL of the pet}}
+        x-internal: {}
+`,
+			expected: `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+servers: [{url: "https://api.example.com/v1"}, {url: "http://api.example.com/v1"}]
+security: [{apiKey: []}]
+paths:
+  /pets:
+    post:
+      parameters:
+        - {name: tags, in: query, schema: {type: array, items: {type: string}}, style: form, explode: true}
+      requestBody:
+        description: The pet
+        required: true
+        x-codegen: pet
+        content:
+          application/json: {schema: {$ref: "#/components/schemas/Pet"}}
+          application/xml: {schema: {$ref: "#/components/schemas/Pet"}}
+      responses:
+        "201":
+          description: created
+          headers: {Location: {description: URL of the pet, schema: {type: string}}}
+          content:
+            application/json: {schema: {$ref: "#/components/schemas/Pet"}}
+        x-internal: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        tag: {type: string, nullable: true}
+  securitySchemes:
+    basic: {type: http, scheme: basic, description: Basic authentication}
+    apiKey: {type: apiKey, name: X-API-Key, in: header}
+    implicit:
+      type: oauth2
+      flows: {implicit: {authorizationUrl: "https://auth.example.com/authorize", scopes: {read: Read}}}
+    password:
+      type: oauth2
+      flows: {password: {tokenUrl: "https://auth.example.com/token", scopes: {}}}
+    application:
+      type: oauth2
+      flows: {clientCredentials: {tokenUrl: "https://auth.example.com/token", scopes: {}}}
+    accessCode:
+      type: oauth2
+      flows:
+        authorizationCode:
+          authorizationUrl: "https://auth.example.com/authorize"
+          tokenUrl: "https://auth.example.com/token"
+          scopes: {write: Write}
+`,
+		},
+		{
+			name: "referenced body parameter",
+			spec: `
+swagger: "2.0"
+info: {title: Pets, version: "1"}
+parameters:
+  Pet: {name: pet, in: body, schema: {type: object}}
+paths:
+  /pets:
+    parameters:
+      - {$ref: "#/parameters/Pet"}
+    post:
+      parameters:
+        - {$ref: "#/parameters/Pet"}
+    put:
+      parameters:
+        - {name: pet, in: body, required: true, schema: {type: string}}
+    patch: {}
+`,
+			expected: `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+servers: [{url: /}]
+paths:
+  /pets:
+    post:
+      requestBody: {$ref: "#/components/requestBodies/Pet"}
+    put:
+      requestBody:
+        required: true
+        content: {application/json: {schema: {type: string}}}
+    patch:
+      requestBody: {$ref: "#/components/requestBodies/Pet"}
+components:
+  requestBodies:
+    Pet:
+      content: {application/json: {schema: {type: object}}}
+`,
+		},
+		{
+			name: "form parameters",
+			spec: `
+swagger: "2.0"
+info: {title: Pets, version: "1"}
+consumes: [application/json]
+parameters:
+  Name: {name: name, in: formData, type: string, description: Name of the pet}
+paths:
+  /pets/{id}/photo:
+    parameters:
+      - {name: name, in: formData, type: string, description: Name of the path item}
+      - {name: tag, in: formData, type: string}
+      - {in: formData, type: string}
+    post:
+      consumes: [application/json, multipart/form-data]
+      parameters:
+        - {$ref: "#/parameters/Name"}
+        - {name: photo, in: formData, type: file, required: true}
+        - {name: id, in: path, type: string, required: true}
+    put:
+      parameters:
+        - {name: photo, in: formData, type: file}
+    patch:
+      parameters:
+        - {name: tag, in: formData, type: integer, required: true}
+`,
+			expected: `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+servers: [{url: /}]
+paths:
+  /pets/{id}/photo:
+    post:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [photo]
+              properties:
+                name: {type: string, description: Name of the pet}
+                photo: {type: string, format: binary}
+                tag: {type: string}
+    put:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                name: {type: string, description: Name of the path item}
+                photo: {type: string, format: binary}
+                tag: {type: string}
+    patch:
+      requestBody:
+        required: true
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [tag]
+              properties:
+                name: {type: string, description: Name of the path item}
+                tag: {type: integer}
+`,
+		},
+		{
+			name:     "OpenAPI 3 spec",
+			spec:     `{"openapi": "3.0.3", "info": {"title": "Pets", "version": "1"}, "paths": {}}`,
+			expected: `{"openapi": "3.0.3", "info": {"title": "Pets", "version": "1"}, "paths": {}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ConvertToOpenAPI3([]byte(tt.spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spec := parseTestSpec(t, string(data))
+			if expected := parseTestSpec(t, tt.expected); !reflect.DeepEqual(spec, expected) {
+				t.Errorf("expected spec\n%v\ngot\n%v", expected, spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,647 @@
+package go_swagger_ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Change is a difference between two versions of a spec.
+type Change struct {
+	// Operation identifies the changed operation (e.g., "GET /pets").
+	Operation string `json:"operation"`
+	// Pointer is the JSON pointer of the changed element relative to the operation
+	// (e.g., "/parameters/query/limit" or "/responses/200/content/application~1json/schema/properties/name").
+	Pointer string `json:"pointer,omitempty"`
+	// Message describes the change.
+	Message string `json:"message"`
+	// Breaking reports whether the change can break existing clients.
+	Breaking bool `json:"breaking"`
+}
+
+// String returns a human-readable representation of the change.
+func (c Change) String() string {
+	severity := "non-breaking"
+	if c.Breaking {
+		severity = "breaking"
+	}
+
+	if c.Pointer == "" {
+		return fmt.Sprintf("[%s] %s: %s", severity, c.Operation, c.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s (%s)", severity, c.Operation, c.Message, c.Pointer)
+}
+
+// SpecDiff contains all changes between two versions of a spec. See DiffSpecs.
+type SpecDiff struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasBreakingChanges reports whether the diff contains breaking changes.
+func (d *SpecDiff) HasBreakingChanges() bool {
+	return len(d.BreakingChanges()) > 0
+}
+
+// BreakingChanges returns all breaking changes.
+func (d *SpecDiff) BreakingChanges() []Change {
+	var changes []Change
+	for _, c := range d.Changes {
+		if c.Breaking {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// Text returns the changes as plain text, one change per line.
+func (d *SpecDiff) Text() string {
+	var sb strings.Builder
+	for _, c := range d.Changes {
+		sb.WriteString(c.String())
+		sb.WriteByte('\n')
+	}
+
+	fmt.Fprintf(&sb, "%d changes (%d breaking)\n", len(d.Changes), len(d.BreakingChanges()))
+
+	return sb.String()
+}
+
+// JSON returns the changes as JSON.
+func (d *SpecDiff) JSON() ([]byte, error) {
+	changes := d.Changes
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	return json.MarshalIndent(struct {
+		Breaking bool     `json:"breaking"`
+		Changes  []Change `json:"changes"`
+	}{d.HasBreakingChanges(), changes}, "", "  ")
+}
+
+// Markdown returns the changes as Markdown, grouped into breaking and non-breaking changes
+// (e.g., to be posted as a pull request comment).
+func (d *SpecDiff) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("## API Changes\n\n")
+
+	if len(d.Changes) == 0 {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+
+	for _, breaking := range []bool{true, false} {
+		title := "Non-breaking changes"
+		if breaking {
+			title = "Breaking changes"
+		}
+
+		var lines []string
+		for _, c := range d.Changes {
+			if c.Breaking != breaking {
+				continue
+			}
+
+			line := fmt.Sprintf("- `%s`: %s", c.Operation, c.Message)
+			if c.Pointer != "" {
+				line += fmt.Sprintf(" (`%s`)", c.Pointer)
+			}
+			lines = append(lines, line)
+		}
+
+		if len(lines) > 0 {
+			fmt.Fprintf(&sb, "### %s (%d)\n\n%s\n\n", title, len(lines), strings.Join(lines, "\n"))
+		}
+	}
+
+	return sb.String()
+}
+
+// DiffSpecs compares two versions of a spec (as YAML or JSON) and returns added, removed and changed
+// operations, parameters, request bodies and responses, including changes of their schemas. Each change
+// is classified as breaking or non-breaking for existing clients. Swagger 2.0 documents are converted to
+// OpenAPI 3.0 before they are compared.
+func DiffSpecs(oldSpec, newSpec []byte) (*SpecDiff, error) {
+	specs := make([]map[string]any, 2)
+	for idx, data := range [][]byte{oldSpec, newSpec} {
+		jsonData, err := yamlOrJSONToJSON(data)
+		if err == nil {
+			jsonData, err = convertSpec(jsonData)
+		}
+		if err == nil {
+			specs[idx], err = parseSpec(jsonData)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse spec: %w", err)
+		}
+	}
+
+	d := &differ{old: specs[0], new: specs[1], diff: &SpecDiff{}}
+	d.diffOperations()
+
+	return d.diff, nil
+}
+
+type differ struct {
+	old, new map[string]any
+	diff     *SpecDiff
+
+	// operation is the operation that is currently compared.
+	operation string
+}
+
+func (d *differ) report(breaking bool, pointer, format string, args ...any) {
+	d.diff.Changes = append(d.diff.Changes, Change{
+		Operation: d.operation,
+		Pointer:   pointer,
+		Message:   fmt.Sprintf(format, args...),
+		Breaking:  breaking,
+	})
+}
+
+type diffOperation struct {
+	spec      map[string]any
+	path      string
+	pathItem  map[string]any
+	operation map[string]any
+}
+
+// operationsByKey returns all operations of a spec by method and path, where all path parameters are
+// replaced with "{}", so that renamed path parameters do not cause operations to be removed and added.
+func operationsByKey(spec map[string]any) map[string]diffOperation {
+	operations := make(map[string]diffOperation)
+	paths, _ := spec["paths"].(map[string]any)
+
+	for path, pathItem := range paths {
+		pathItemObj := deref(spec, pathItem)
+		for _, method := range httpMethods {
+			if operation, ok := pathItemObj[method].(map[string]any); ok {
+				key := strings.ToUpper(method) + " " + pathParamPattern.ReplaceAllString(path, "{}")
+				operations[key] = diffOperation{spec: spec, path: path, pathItem: pathItemObj, operation: operation}
+			}
+		}
+	}
+
+	return operations
+}
+
+func (d *differ) diffOperations() {
+	oldOperations, newOperations := operationsByKey(d.old), operationsByKey(d.new)
+
+	keys := make(map[string]struct{})
+	for key := range oldOperations {
+		keys[key] = struct{}{}
+	}
+	for key := range newOperations {
+		keys[key] = struct{}{}
+	}
+
+	for _, key := range sortedKeys(keys) {
+		oldOp, inOld := oldOperations[key]
+		newOp, inNew := newOperations[key]
+		method, _, _ := strings.Cut(key, " ")
+
+		switch {
+		case !inNew:
+			d.operation = method + " " + oldOp.path
+			d.report(true, "", "operation removed")
+		case !inOld:
+			d.operation = method + " " + newOp.path
+			d.report(false, "", "operation added")
+		default:
+			d.operation = method + " " + newOp.path
+			d.diffOperation(oldOp, newOp)
+		}
+	}
+}
+
+func (d *differ) diffOperation(oldOp, newOp diffOperation) {
+	oldDeprecated, _ := oldOp.operation["deprecated"].(bool)
+	newDeprecated, _ := newOp.operation["deprecated"].(bool)
+	if !oldDeprecated && newDeprecated {
+		d.report(false, "/deprecated", "operation deprecated")
+	}
+
+	if oldID, newID := oldOp.operation["operationId"], newOp.operation["operationId"]; oldID != nil && oldID != newID {
+		// Generated clients use operation IDs as method names.
+		d.report(true, "/operationId", "operationId changed from %v to %v", oldID, newID)
+	}
+
+	if len(securityOf(oldOp)) == 0 && len(securityOf(newOp)) > 0 {
+		d.report(true, "/security", "operation requires authentication")
+	}
+
+	d.diffParameters(oldOp, newOp)
+	d.diffRequestBody(oldOp, newOp)
+	d.diffResponses(oldOp, newOp)
+}
+
+// securityOf returns the security requirements of an operation. An empty requirement
+// object makes the security requirements optional.
+func securityOf(op diffOperation) []any {
+	security, ok := op.operation["security"].([]any)
+	if !ok {
+		security, _ = op.spec["security"].([]any)
+	}
+
+	for _, requirement := range security {
+		if requirementObj, ok := requirement.(map[string]any); ok && len(requirementObj) == 0 {
+			return nil
+		}
+	}
+
+	return security
+}
+
+// parametersOf returns the parameters of an operation (including the parameters of its path item)
+// by location and name (e.g., "query/limit"). Path parameters are identified by their position.
+func parametersOf(op diffOperation) map[string]map[string]any {
+	params := make(map[string]map[string]any)
+
+	pathParamNames := make(map[string]string)
+	for idx, match := range pathParamPattern.FindAllStringSubmatch(op.path, -1) {
+		pathParamNames[match[1]] = fmt.Sprintf("%d", idx)
+	}
+
+	for _, list := range []any{op.pathItem["parameters"], op.operation["parameters"]} {
+		values, _ := list.([]any)
+		for _, value := range values {
+			param := deref(op.spec, value)
+			name, _ := param["name"].(string)
+			in, _ := param["in"].(string)
+
+			if position, ok := pathParamNames[name]; ok && in == "path" {
+				name = "{" + position + "}"
+			}
+			params[in+"/"+name] = param
+		}
+	}
+
+	return params
+}
+
+func (d *differ) diffParameters(oldOp, newOp diffOperation) {
+	oldParams, newParams := parametersOf(oldOp), parametersOf(newOp)
+
+	keys := make(map[string]struct{})
+	for key := range oldParams {
+		keys[key] = struct{}{}
+	}
+	for key := range newParams {
+		keys[key] = struct{}{}
+	}
+
+	for _, key := range sortedKeys(keys) {
+		oldParam, inOld := oldParams[key]
+		newParam, inNew := newParams[key]
+		in, _, _ := strings.Cut(key, "/")
+
+		name, _ := newParam["name"].(string)
+		if !inNew {
+			name, _ = oldParam["name"].(string)
+		}
+		pointer := "/parameters/" + in + "/" + escapeJSONPointer(name)
+
+		oldRequired, _ := oldParam["required"].(bool)
+		newRequired, _ := newParam["required"].(bool)
+
+		switch {
+		case !inNew:
+			d.report(true, pointer, "%s parameter %q removed", in, name)
+		case !inOld:
+			if newRequired {
+				d.report(true, pointer, "required %s parameter %q added", in, name)
+			} else {
+				d.report(false, pointer, "optional %s parameter %q added", in, name)
+			}
+		default:
+			if !oldRequired && newRequired {
+				d.report(true, pointer, "%s parameter %q became required", in, name)
+			} else if oldRequired && !newRequired {
+				d.report(false, pointer, "%s parameter %q became optional", in, name)
+			}
+
+			d.diffSchema(d.schemaPair(oldParam["schema"], newParam["schema"]), pointer+"/schema", true)
+		}
+	}
+}
+
+func (d *differ) diffRequestBody(oldOp, newOp diffOperation) {
+	oldBody := deref(oldOp.spec, oldOp.operation["requestBody"])
+	newBody := deref(newOp.spec, newOp.operation["requestBody"])
+	oldRequired, _ := oldBody["required"].(bool)
+	newRequired, _ := newBody["required"].(bool)
+
+	switch {
+	case oldBody == nil && newBody == nil:
+		return
+	case newBody == nil:
+		d.report(false, "/requestBody", "request body removed")
+		return
+	case oldBody == nil:
+		if newRequired {
+			d.report(true, "/requestBody", "required request body added")
+		} else {
+			d.report(false, "/requestBody", "optional request body added")
+		}
+		return
+	}
+
+	if !oldRequired && newRequired {
+		d.report(true, "/requestBody", "request body became required")
+	}
+
+	d.diffContent(oldBody, newBody, "/requestBody", true)
+}
+
+func (d *differ) diffResponses(oldOp, newOp diffOperation) {
+	oldResponses, _ := oldOp.operation["responses"].(map[string]any)
+	newResponses, _ := newOp.operation["responses"].(map[string]any)
+
+	codes := make(map[string]struct{})
+	for code := range oldResponses {
+		codes[code] = struct{}{}
+	}
+	for code := range newResponses {
+		codes[code] = struct{}{}
+	}
+
+	for _, code := range sortedKeys(codes) {
+		if strings.HasPrefix(code, "x-") {
+			continue
+		}
+
+		oldResponse, inOld := oldResponses[code]
+		newResponse, inNew := newResponses[code]
+		pointer := "/responses/" + escapeJSONPointer(code)
+
+		switch {
+		case !inNew:
+			// Clients expect successful responses, but usually handle missing error responses.
+			d.report(strings.HasPrefix(code, "2"), pointer, "response %s removed", code)
+		case !inOld:
+			d.report(false, pointer, "response %s added", code)
+		default:
+			d.diffContent(deref(oldOp.spec, oldResponse), deref(newOp.spec, newResponse), pointer, false)
+		}
+	}
+}
+
+// diffContent compares the media types of a request body or response.
+func (d *differ) diffContent(oldObj, newObj map[string]any, pointer string, request bool) {
+	oldContent, _ := oldObj["content"].(map[string]any)
+	newContent, _ := newObj["content"].(map[string]any)
+
+	mediaTypes := make(map[string]struct{})
+	for mediaType := range oldContent {
+		mediaTypes[mediaType] = struct{}{}
+	}
+	for mediaType := range newContent {
+		mediaTypes[mediaType] = struct{}{}
+	}
+
+	for _, mediaType := range sortedKeys(mediaTypes) {
+		oldMediaType, inOld := oldContent[mediaType].(map[string]any)
+		newMediaType, inNew := newContent[mediaType].(map[string]any)
+		mediaTypePointer := pointer + "/content/" + escapeJSONPointer(mediaType)
+
+		switch {
+		case !inNew:
+			d.report(true, mediaTypePointer, "media type %s removed", mediaType)
+		case !inOld:
+			d.report(false, mediaTypePointer, "media type %s added", mediaType)
+		default:
+			d.diffSchema(d.schemaPair(oldMediaType["schema"], newMediaType["schema"]), mediaTypePointer+"/schema", request)
+		}
+	}
+}
+
+type schemaPair struct {
+	old, new map[string]any
+	// visited contains the references of all schemas that have been compared on the
+	// way to this pair, so that recursive schemas are only compared once.
+	visited map[[2]string]struct{}
+}
+
+func (d *differ) schemaPair(oldSchema, newSchema any) schemaPair {
+	return d.childPair(schemaPair{visited: make(map[[2]string]struct{})}, oldSchema, newSchema)
+}
+
+// childPair returns the pair of sub-schemas of a pair. The old or new schema is nil,
+// if the pair has already been compared.
+func (d *differ) childPair(parent schemaPair, oldSchema, newSchema any) schemaPair {
+	refs := [2]string{fmt.Sprint(lookup(oldSchema, "$ref")), fmt.Sprint(lookup(newSchema, "$ref"))}
+	if _, ok := parent.visited[refs]; ok && (refs[0] != "<nil>" || refs[1] != "<nil>") {
+		return schemaPair{visited: parent.visited}
+	}
+
+	visited := make(map[[2]string]struct{}, len(parent.visited)+1)
+	for key := range parent.visited {
+		visited[key] = struct{}{}
+	}
+	visited[refs] = struct{}{}
+
+	return schemaPair{old: deref(d.old, oldSchema), new: deref(d.new, newSchema), visited: visited}
+}
+
+// diffSchema compares two schemas. Request schemas must not become more strict, while response
+// schemas must not return values that existing clients do not expect.
+func (d *differ) diffSchema(pair schemaPair, pointer string, request bool) {
+	oldSchema, newSchema := pair.old, pair.new
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+
+	if oldType, newType := schemaType(oldSchema), schemaType(newSchema); oldType != newType && oldType != "" {
+		d.report(true, pointer, "type changed from %s to %s", oldType, describeType(newType))
+		return
+	}
+
+	if oldFormat, newFormat := oldSchema["format"], newSchema["format"]; oldFormat != newFormat && oldFormat != nil {
+		d.report(true, pointer, "format changed from %v to %v", oldFormat, newFormat)
+	}
+
+	if oldNullable, newNullable := allowsNull(oldSchema), allowsNull(newSchema); oldNullable != newNullable {
+		if request && oldNullable {
+			d.report(true, pointer, "null is no longer allowed")
+		} else if !request && newNullable {
+			d.report(true, pointer, "value may be null")
+		} else {
+			d.report(false, pointer, "nullable changed to %v", newNullable)
+		}
+	}
+
+	d.diffEnum(oldSchema, newSchema, pointer, request)
+	d.diffLimits(oldSchema, newSchema, pointer, request)
+
+	if _, ok := oldSchema["items"]; ok {
+		d.diffSchema(d.childPair(pair, oldSchema["items"], newSchema["items"]), pointer+"/items", request)
+	}
+
+	d.diffProperties(pair, pointer, request)
+}
+
+func describeType(t string) string {
+	if t == "" {
+		return "any"
+	}
+	return t
+}
+
+func (d *differ) diffEnum(oldSchema, newSchema map[string]any, pointer string, request bool) {
+	oldEnum, oldOK := oldSchema["enum"].([]any)
+	newEnum, newOK := newSchema["enum"].([]any)
+	if !oldOK && !newOK {
+		return
+	}
+
+	if !oldOK {
+		// A new enum restricts values that were allowed before.
+		d.report(request, pointer+"/enum", "values restricted to %v", newEnum)
+		return
+	}
+
+	if !newOK {
+		d.report(!request, pointer+"/enum", "values are no longer restricted")
+		return
+	}
+
+	for _, value := range oldEnum {
+		if !containsValue(newEnum, value) {
+			d.report(request, pointer+"/enum", "enum value %v removed", value)
+		}
+	}
+
+	for _, value := range newEnum {
+		if !containsValue(oldEnum, value) {
+			// Clients may not be able to handle unknown values in responses.
+			d.report(!request, pointer+"/enum", "enum value %v added", value)
+		}
+	}
+}
+
+// diffLimits compares the validation keywords of two schemas. Stricter limits break requests.
+func (d *differ) diffLimits(oldSchema, newSchema map[string]any, pointer string, request bool) {
+	for _, limit := range []struct {
+		key   string
+		lower bool // lower limits (e.g., minimum) are stricter if they increase
+	}{
+		{"minimum", true}, {"minLength", true}, {"minItems", true}, {"minProperties", true},
+		{"maximum", false}, {"maxLength", false}, {"maxItems", false}, {"maxProperties", false},
+	} {
+		oldValue, oldOK := oldSchema[limit.key].(float64)
+		newValue, newOK := newSchema[limit.key].(float64)
+
+		var stricter bool
+		switch {
+		case !newOK || (oldOK && oldValue == newValue):
+			continue
+		case !oldOK:
+			stricter = true
+		case limit.lower:
+			stricter = newValue > oldValue
+		default:
+			stricter = newValue < oldValue
+		}
+
+		if oldOK {
+			d.report(request && stricter, pointer+"/"+limit.key, "%s changed from %v to %v", limit.key, oldValue, newValue)
+		} else {
+			d.report(request && stricter, pointer+"/"+limit.key, "%s %v added", limit.key, newValue)
+		}
+	}
+
+	if oldPattern, newPattern := oldSchema["pattern"], newSchema["pattern"]; newPattern != nil && !reflect.DeepEqual(oldPattern, newPattern) {
+		d.report(request, pointer+"/pattern", "pattern changed to %v", newPattern)
+	}
+}
+
+func (d *differ) diffProperties(pair schemaPair, pointer string, request bool) {
+	oldProperties := mergedProperties(d.old, pair.old)
+	newProperties := mergedProperties(d.new, pair.new)
+	oldRequired := requiredProperties(d.old, pair.old)
+	newRequired := requiredProperties(d.new, pair.new)
+
+	names := make(map[string]struct{})
+	for name := range oldProperties {
+		names[name] = struct{}{}
+	}
+	for name := range newProperties {
+		names[name] = struct{}{}
+	}
+
+	for _, name := range sortedKeys(names) {
+		oldProperty, inOld := oldProperties[name]
+		newProperty, inNew := newProperties[name]
+		propertyPointer := pointer + "/properties/" + escapeJSONPointer(name)
+		_, wasRequired := oldRequired[name]
+		_, isRequired := newRequired[name]
+
+		switch {
+		case !inNew:
+			d.report(!request, propertyPointer, "property %q removed", name)
+		case !inOld:
+			if request && isRequired {
+				d.report(true, propertyPointer, "required property %q added", name)
+			} else {
+				d.report(false, propertyPointer, "property %q added", name)
+			}
+		default:
+			switch {
+			case request && !wasRequired && isRequired:
+				d.report(true, propertyPointer, "property %q became required", name)
+			case !request && wasRequired && !isRequired:
+				d.report(true, propertyPointer, "property %q became optional", name)
+			case wasRequired != isRequired:
+				d.report(false, propertyPointer, "property %q became %s", name, map[bool]string{true: "required", false: "optional"}[isRequired])
+			}
+
+			d.diffSchema(d.childPair(pair, oldProperty, newProperty), propertyPointer, request)
+		}
+	}
+}
+
+// mergedProperties returns the properties of a schema, including the properties of its allOf schemas.
+func mergedProperties(spec map[string]any, schema map[string]any) map[string]any {
+	properties := make(map[string]any)
+	for name, property := range lookupMap(schema, "properties") {
+		properties[name] = property
+	}
+
+	allOf, _ := schema["allOf"].([]any)
+	for _, subSchema := range allOf {
+		for name, property := range lookupMap(deref(spec, subSchema), "properties") {
+			if _, ok := properties[name]; !ok {
+				properties[name] = property
+			}
+		}
+	}
+
+	return properties
+}
+
+func requiredProperties(spec map[string]any, schema map[string]any) map[string]struct{} {
+	required := make(map[string]struct{})
+
+	schemas := []map[string]any{schema}
+	allOf, _ := schema["allOf"].([]any)
+	for _, subSchema := range allOf {
+		schemas = append(schemas, deref(spec, subSchema))
+	}
+
+	for _, s := range schemas {
+		names, _ := s["required"].([]any)
+		for _, name := range names {
+			required[fmt.Sprint(name)] = struct{}{}
+		}
+	}
+
+	return required
+}
+
+func lookupMap(v map[string]any, key string) map[string]any {
+	m, _ := v[key].(map[string]any)
+	return m
+}
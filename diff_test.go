@@ -0,0 +1,268 @@
+package go_swagger_ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+// diffTestSpec returns a spec with the operation "POST /pets/{id}" and the given components.
+func diffTestSpec(operation, components string) []byte {
+	if components == "" {
+		components = "{}"
+	}
+
+	return []byte(`{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1"},
+  "paths": {"/pets/{id}": {"post": ` + operation + `}},
+  "components": ` + components + `
+}`)
+}
+
+func TestDiffSpecs(t *testing.T) {
+	const (
+		operation  = "POST /pets/{id}"
+		petRequest = `"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}`
+		petOK      = `"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}`
+		petSchemas = `{"schemas": {"Pet": {"type": "object", "required": ["name"], "properties": {
+  "name": {"type": "string"},
+  "status": {"type": "string", "enum": ["available", "sold"]}
+}}}}`
+	)
+
+	tests := []struct {
+		name                    string
+		oldOperation, oldSchema string
+		newOperation, newSchema string
+		expected                []Change
+	}{
+		{
+			name:         "no changes",
+			oldOperation: `{` + petRequest + `, "responses": {` + petOK + `}}`,
+			oldSchema:    petSchemas,
+			newOperation: `{` + petRequest + `, "responses": {` + petOK + `}}`,
+			newSchema:    petSchemas,
+		},
+		{
+			name:         "operation deprecated",
+			oldOperation: `{"responses": {}}`,
+			newOperation: `{"deprecated": true, "responses": {}}`,
+			expected:     []Change{{Pointer: "/deprecated", Message: "operation deprecated"}},
+		},
+		{
+			name:         "operationId changed",
+			oldOperation: `{"operationId": "updatePet", "responses": {}}`,
+			newOperation: `{"operationId": "replacePet", "responses": {}}`,
+			expected:     []Change{{Pointer: "/operationId", Message: "operationId changed from updatePet to replacePet", Breaking: true}},
+		},
+		{
+			name:         "authentication required",
+			oldOperation: `{"responses": {}}`,
+			newOperation: `{"security": [{"apiKey": []}], "responses": {}}`,
+			expected:     []Change{{Pointer: "/security", Message: "operation requires authentication", Breaking: true}},
+		},
+		{
+			name:         "parameters added",
+			oldOperation: `{"responses": {}}`,
+			newOperation: `{"parameters": [
+  {"name": "limit", "in": "query", "schema": {"type": "integer"}},
+  {"name": "X-Request-ID", "in": "header", "required": true, "schema": {"type": "string"}}
+], "responses": {}}`,
+			expected: []Change{
+				{Pointer: "/parameters/header/X-Request-ID", Message: `required header parameter "X-Request-ID" added`, Breaking: true},
+				{Pointer: "/parameters/query/limit", Message: `optional query parameter "limit" added`},
+			},
+		},
+		{
+			name:         "parameters removed and changed",
+			oldOperation: `{"parameters": [{"name": "limit", "in": "query"}, {"name": "offset", "in": "query", "required": true}, {"name": "sort", "in": "query"}], "responses": {}}`,
+			newOperation: `{"parameters": [{"name": "offset", "in": "query"}, {"name": "sort", "in": "query", "required": true}], "responses": {}}`,
+			expected: []Change{
+				{Pointer: "/parameters/query/limit", Message: `query parameter "limit" removed`, Breaking: true},
+				{Pointer: "/parameters/query/offset", Message: `query parameter "offset" became optional`},
+				{Pointer: "/parameters/query/sort", Message: `query parameter "sort" became required`, Breaking: true},
+			},
+		},
+		{
+			name:         "parameter limits",
+			oldOperation: `{"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100}}], "responses": {}}`,
+			newOperation: `{"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 50, "minimum": 1}}], "responses": {}}`,
+			expected: []Change{
+				{Pointer: "/parameters/query/limit/schema/minimum", Message: "minimum 1 added", Breaking: true},
+				{Pointer: "/parameters/query/limit/schema/maximum", Message: "maximum changed from 100 to 50", Breaking: true},
+			},
+		},
+		{
+			name:         "parameter limits relaxed",
+			oldOperation: `{"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 50}}], "responses": {}}`,
+			newOperation: `{"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100}}], "responses": {}}`,
+			expected:     []Change{{Pointer: "/parameters/query/limit/schema/maximum", Message: "maximum changed from 50 to 100"}},
+		},
+		{
+			name:         "parameter type changed",
+			oldOperation: `{"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer"}}], "responses": {}}`,
+			newOperation: `{"parameters": [{"name": "limit", "in": "query", "schema": {"type": "string"}}], "responses": {}}`,
+			expected:     []Change{{Pointer: "/parameters/query/limit/schema", Message: "type changed from integer to string", Breaking: true}},
+		},
+		{
+			name:         "optional request body added",
+			oldOperation: `{"responses": {}}`,
+			newOperation: `{` + petRequest + `, "responses": {}}`,
+			newSchema:    petSchemas,
+			expected:     []Change{{Pointer: "/requestBody", Message: "optional request body added"}},
+		},
+		{
+			name:         "request body became required",
+			oldOperation: `{` + petRequest + `, "responses": {}}`,
+			oldSchema:    petSchemas,
+			newOperation: `{"requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}, "responses": {}}`,
+			newSchema:    petSchemas,
+			expected:     []Change{{Pointer: "/requestBody", Message: "request body became required", Breaking: true}},
+		},
+		{
+			name:         "request body removed",
+			oldOperation: `{` + petRequest + `, "responses": {}}`,
+			oldSchema:    petSchemas,
+			newOperation: `{"responses": {}}`,
+			expected:     []Change{{Pointer: "/requestBody", Message: "request body removed"}},
+		},
+		{
+			name:         "request schema changed",
+			oldOperation: `{` + petRequest + `, "responses": {}}`,
+			oldSchema:    petSchemas,
+			newOperation: `{` + petRequest + `, "responses": {}}`,
+			newSchema: `{"schemas": {"Pet": {"type": "object", "required": ["name", "tag"], "properties": {
+  "name": {"type": "string"},
+  "status": {"type": "string", "enum": ["available", "pending"]},
+  "tag": {"type": "string"},
+  "age": {"type": "integer"}
+}}}}`,
+			expected: []Change{
+				{Pointer: "/requestBody/content/application~1json/schema/properties/age", Message: `property "age" added`},
+				{Pointer: "/requestBody/content/application~1json/schema/properties/status/enum", Message: "enum value sold removed", Breaking: true},
+				{Pointer: "/requestBody/content/application~1json/schema/properties/status/enum", Message: "enum value pending added"},
+				{Pointer: "/requestBody/content/application~1json/schema/properties/tag", Message: `required property "tag" added`, Breaking: true},
+			},
+		},
+		{
+			name:         "response schema changed",
+			oldOperation: `{"responses": {` + petOK + `}}`,
+			oldSchema:    petSchemas,
+			newOperation: `{"responses": {` + petOK + `}}`,
+			newSchema: `{"schemas": {"Pet": {"type": "object", "properties": {
+  "name": {"type": "string"},
+  "status": {"type": "string", "enum": ["available", "pending"]},
+  "age": {"type": "integer"}
+}}}}`,
+			expected: []Change{
+				{Pointer: "/responses/200/content/application~1json/schema/properties/age", Message: `property "age" added`},
+				{Pointer: "/responses/200/content/application~1json/schema/properties/name", Message: `property "name" became optional`, Breaking: true},
+				{Pointer: "/responses/200/content/application~1json/schema/properties/status/enum", Message: "enum value sold removed"},
+				{Pointer: "/responses/200/content/application~1json/schema/properties/status/enum", Message: "enum value pending added", Breaking: true},
+			},
+		},
+		{
+			name:         "response property removed",
+			oldOperation: `{"responses": {` + petOK + `}}`,
+			oldSchema:    petSchemas,
+			newOperation: `{"responses": {` + petOK + `}}`,
+			newSchema:    `{"schemas": {"Pet": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}}}`,
+			expected: []Change{
+				{Pointer: "/responses/200/content/application~1json/schema/properties/status", Message: `property "status" removed`, Breaking: true},
+			},
+		},
+		{
+			name:         "media type removed",
+			oldOperation: `{"responses": {"200": {"description": "ok", "content": {"application/json": {}, "application/xml": {}}}}}`,
+			newOperation: `{"responses": {"200": {"description": "ok", "content": {"application/json": {}, "text/plain": {}}}}}`,
+			expected: []Change{
+				{Pointer: "/responses/200/content/application~1xml", Message: "media type application/xml removed", Breaking: true},
+				{Pointer: "/responses/200/content/text~1plain", Message: "media type text/plain added"},
+			},
+		},
+		{
+			name:         "responses added and removed",
+			oldOperation: `{"responses": {"200": {"description": "ok"}, "404": {"description": "not found"}}}`,
+			newOperation: `{"responses": {"201": {"description": "created"}}}`,
+			expected: []Change{
+				{Pointer: "/responses/200", Message: "response 200 removed", Breaking: true},
+				{Pointer: "/responses/201", Message: "response 201 added"},
+				{Pointer: "/responses/404", Message: "response 404 removed"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldSpec := diffTestSpec(tt.oldOperation, tt.oldSchema)
+			newSpec := diffTestSpec(tt.newOperation, tt.newSchema)
+
+			diff, err := DiffSpecs(oldSpec, newSpec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var expected []Change
+			for _, change := range tt.expected {
+				change.Operation = operation
+				expected = append(expected, change)
+			}
+
+			if !reflect.DeepEqual(diff.Changes, expected) {
+				t.Errorf("expected changes\n%v\ngot\n%v", expected, diff.Changes)
+			}
+
+			breaking := false
+			for _, change := range expected {
+				breaking = breaking || change.Breaking
+			}
+			if diff.HasBreakingChanges() != breaking {
+				t.Errorf("expected HasBreakingChanges to be %v", breaking)
+			}
+		})
+	}
+}
+
+func TestDiffSpecsOperations(t *testing.T) {
+	oldSpec := []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    get: {responses: {}}
+    delete: {responses: {}}
+  /pets/{id}:
+    get: {operationId: getPet, responses: {}}
+`)
+	newSpec := []byte(`
+swagger: "2.0"
+info: {title: Pets, version: "2"}
+paths:
+  /pets:
+    get: {responses: {}}
+    post: {responses: {}}
+  /pets/{petId}:
+    get: {operationId: getPet, responses: {}}
+`)
+
+	diff, err := DiffSpecs(oldSpec, newSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Change{
+		{Operation: "DELETE /pets", Message: "operation removed", Breaking: true},
+		{Operation: "POST /pets", Message: "operation added"},
+	}
+	if !reflect.DeepEqual(diff.Changes, expected) {
+		t.Errorf("expected changes\n%v\ngot\n%v", expected, diff.Changes)
+	}
+
+	if breaking := diff.BreakingChanges(); !reflect.DeepEqual(breaking, expected[:1]) {
+		t.Errorf("expected breaking changes %v, got %v", expected[:1], breaking)
+	}
+
+	if _, err := DiffSpecs(oldSpec, []byte("{")); err == nil {
+		t.Error("expected an error for an invalid spec")
+	}
+}
@@ -0,0 +1,169 @@
+package go_swagger_ui
+
+import (
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// filesEndpoint is the path prefix the files of the spec directory are served on.
+const filesEndpoint = "_files"
+
+// specFileContentTypes contains the file extensions of files that are served by
+// WithSpecFiles and their content types.
+var specFileContentTypes = map[string]string{
+	".json": "application/json",
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+}
+
+// WithSpecFiles serves the JSON and YAML files in the directory of the spec set with WithSpecFS or
+// WithSpecFilePath (including subdirectories) on "_files/", so that Swagger UI can resolve relative
+// references to other files itself. This is an alternative to bundling (see WithBundleMode) that
+// keeps referenced files identical to the source files. Swagger UI loads the spec from its URL in
+// this case. Spec transformations, filters and server rewrites only apply to the spec document itself.
+//
+// Files outside of the spec directory, hidden files and files with other extensions are not served.
+func WithSpecFiles() Option {
+	return func(cfg *uiConfig) {
+		cfg.specFiles = true
+	}
+}
+
+// servesSpecFiles reports whether the files of the spec directory are served.
+func servesSpecFiles(cfg *uiConfig) bool {
+	return cfg.specFiles && (cfg.specFS != nil || cfg.specFilePath != "")
+}
+
+// specFilesRoot returns the directory of the spec set with WithSpecFS or WithSpecFilePath
+// and the file name of the spec in this directory.
+func specFilesRoot(cfg *uiConfig) (fs.FS, string) {
+	if cfg.specFS == nil {
+		return os.DirFS(filepath.Dir(cfg.specFilePath)), filepath.Base(cfg.specFilePath)
+	}
+
+	name := path.Clean(cfg.specFSName)
+	dir, err := fs.Sub(cfg.specFS, path.Dir(name))
+	if err != nil {
+		return cfg.specFS, name
+	}
+
+	return dir, path.Base(name)
+}
+
+// loadSpecFile reads the spec set with WithSpecFS or WithSpecFilePath. References to other files
+// are bundled, unless the files are served by the handler (see WithSpecFiles).
+func loadSpecFile(cfg *uiConfig) ([]byte, error) {
+	fsys, name := specFilesRoot(cfg)
+	if cfg.specFiles {
+		return fs.ReadFile(fsys, name)
+	}
+
+	return Bundle(fsys, name, cfg.bundleMode)
+}
+
+// specFilesURL returns the URL of the spec served by the files endpoint relative to the index.html page.
+func specFilesURL(cfg *uiConfig) string {
+	_, name := specFilesRoot(cfg)
+	return cfg.basePath + filesEndpoint + "/" + (&url.URL{Path: name}).EscapedPath()
+}
+
+type specFilesHandler struct {
+	cfg *uiConfig
+}
+
+// ServeHTTP serves the file with the path of the request URL (e.g., "/schemas/pet.yaml"). The
+// spec itself is served as prepared for the requesting user (see specForRequest).
+func (h *specFilesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		sendStatus(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	fsys, root := specFilesRoot(h.cfg)
+
+	name, ok := h.fileName(r.URL.Path)
+	if !ok {
+		sendStatus(w, http.StatusNotFound)
+		return
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if name == root {
+		data, err = specForRequest(r, h.cfg)
+	} else if err = h.checkSymlinks(name); err == nil {
+		data, err = fs.ReadFile(fsys, name)
+	}
+
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Error("error reading spec file", "file", name, "err", err.Error())
+		}
+		sendError(w, err)
+		return
+	}
+
+	contentType := specFileContentTypes[strings.ToLower(path.Ext(name))]
+	if name == root {
+		// The spec has been converted to JSON.
+		contentType = "application/json"
+	}
+
+	w.Header().Set("Cache-Control", "private, no-cache")
+	setSecurityHeaders(w, h.cfg, "")
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
+
+// fileName returns the slash-separated name of the requested file relative to the spec directory.
+// It returns false, if the file must not be served.
+func (h *specFilesHandler) fileName(urlPath string) (string, bool) {
+	name := strings.TrimPrefix(urlPath, "/")
+	if !fs.ValidPath(name) || name == "." || strings.Contains(name, `\`) {
+		return "", false
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return "", false
+		}
+	}
+
+	if _, ok := specFileContentTypes[strings.ToLower(path.Ext(name))]; !ok {
+		return "", false
+	}
+
+	return name, true
+}
+
+// checkSymlinks makes sure that a file in the spec directory of WithSpecFilePath
+// is not a symbolic link to a file outside of the directory.
+func (h *specFilesHandler) checkSymlinks(name string) error {
+	if h.cfg.specFS != nil {
+		return nil
+	}
+
+	dir, err := filepath.EvalSymlinks(filepath.Dir(h.cfg.specFilePath))
+	if err != nil {
+		return err
+	}
+
+	file, err := filepath.EvalSymlinks(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(file, dir+string(filepath.Separator)) {
+		return fs.ErrNotExist
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package go_swagger_ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpecFilesHandler(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "api")
+	outside := filepath.Join(root, "secret.yaml")
+
+	files := map[string]string{
+		filepath.Join(dir, "openapi.yaml"):        testSpec,
+		filepath.Join(dir, "schemas", "pet.yaml"): "type: object\n",
+		filepath.Join(dir, ".private.yaml"):       "password: secret\n",
+		filepath.Join(dir, "notes.txt"):           "notes\n",
+		outside:                                   "password: secret\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Symlink(outside, filepath.Join(dir, "outside.yaml")); err != nil {
+		t.Skipf("symbolic links are not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "schemas", "pet.yaml"), filepath.Join(dir, "inside.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(root, filepath.Join(dir, "parent")); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewHandler(WithSpecFilePath(filepath.Join(dir, "openapi.yaml")), WithSpecFiles())
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"spec", http.MethodGet, "/_files/openapi.yaml", http.StatusOK},
+		{"referenced file", http.MethodGet, "/_files/schemas/pet.yaml", http.StatusOK},
+		{"symbolic link inside the directory", http.MethodGet, "/_files/inside.yaml", http.StatusOK},
+		{"path traversal", http.MethodGet, "/_files/../secret.yaml", http.StatusNotFound},
+		{"nested path traversal", http.MethodGet, "/_files/schemas/../../secret.yaml", http.StatusNotFound},
+		{"encoded path traversal", http.MethodGet, "/_files/schemas/%2e%2e/%2e%2e/secret.yaml", http.StatusNotFound},
+		{"backslash path traversal", http.MethodGet, `/_files/schemas\..\..\secret.yaml`, http.StatusNotFound},
+		{"symbolic link to a file outside of the directory", http.MethodGet, "/_files/outside.yaml", http.StatusNotFound},
+		{"symbolic link to a parent directory", http.MethodGet, "/_files/parent/secret.yaml", http.StatusNotFound},
+		{"hidden file", http.MethodGet, "/_files/.private.yaml", http.StatusNotFound},
+		{"other file extension", http.MethodGet, "/_files/notes.txt", http.StatusNotFound},
+		{"missing file", http.MethodGet, "/_files/schemas/user.yaml", http.StatusNotFound},
+		{"other method", http.MethodPost, "/_files/schemas/pet.yaml", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
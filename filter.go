@@ -0,0 +1,208 @@
+package go_swagger_ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// SpecFilter is called for every request of a user that loads the spec in Swagger UI and
+// decides which operations the user is allowed to see. See WithSpecFilter.
+type SpecFilter func(r *http.Request) (SpecFilterResult, error)
+
+// SpecFilterResult is the result of a SpecFilter.
+type SpecFilterResult struct {
+	// Key identifies this filter result. Filtered specs are cached by key, so all requests that result in
+	// the same key must keep the same set of operations (e.g., a sorted list of the user's roles).
+	Key string
+
+	// Keep reports whether an operation should remain in the spec. If Keep is nil, all operations are kept.
+	Keep func(op Operation) bool
+}
+
+// WithSpecFilter sets a filter that is called for every request and decides which operations of the spec
+// are visible to the requesting user. Operations that are not kept are removed from the spec along with
+// path items, tags and reusable components (e.g., schemas) that are not used anymore.
+// Filtered specs are cached by SpecFilterResult.Key.
+// This option only has an effect on specs that are embedded into Swagger UI (see WithSpec and WithSpecFilePath).
+// Specs that are loaded by Swagger UI from a URL (see WithSpecURL and WithSpecURLs) are not filtered.
+// See FilterByTags and FilterByExtension for ready-made filters.
+func WithSpecFilter(filter SpecFilter) Option {
+	return func(cfg *uiConfig) {
+		cfg.specFilter = filter
+	}
+}
+
+// FilterByTags returns a SpecFilter that only keeps operations that have at least
+// one of the tags returned by the tags function for the request.
+func FilterByTags(tags func(r *http.Request) []string) SpecFilter {
+	return func(r *http.Request) (SpecFilterResult, error) {
+		allowed := toSet(tags(r))
+
+		return SpecFilterResult{
+			Key: "tags:" + setKey(allowed),
+			Keep: func(op Operation) bool {
+				for _, tag := range op.Tags {
+					if _, ok := allowed[tag]; ok {
+						return true
+					}
+				}
+				return false
+			},
+		}, nil
+	}
+}
+
+// FilterByExtension returns a SpecFilter that filters operations by the value of a vendor
+// extension (e.g., "x-audience"). The extension value can either be a string or a list of strings.
+// An operation is kept if any of its extension values is contained in the values returned by the
+// values function for the request. Operations that do not have the extension are always kept.
+// The extension can also be set on a path item to apply it to all of its operations.
+func FilterByExtension(extension string, values func(r *http.Request) []string) SpecFilter {
+	return func(r *http.Request) (SpecFilterResult, error) {
+		allowed := toSet(values(r))
+
+		return SpecFilterResult{
+			Key: "extension:" + strconv.Quote(extension) + ":" + setKey(allowed),
+			Keep: func(op Operation) bool {
+				value, ok := op.Extensions[extension]
+				if !ok {
+					return true
+				}
+
+				for _, v := range extensionValues(value) {
+					if _, ok := allowed[v]; ok {
+						return true
+					}
+				}
+				return false
+			},
+		}, nil
+	}
+}
+
+func extensionValues(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			if s, ok := elem.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+
+	return set
+}
+
+// setKey returns a cache key for a set of values. The values are encoded as JSON array, so that
+// values containing separators cannot result in the same key as other sets (e.g., {"a,b"} and {"a", "b"}).
+func setKey(set map[string]struct{}) string {
+	values := make([]string, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	key, _ := json.Marshal(values)
+	return string(key)
+}
+
+// maxSpecFilterCacheEntries is the maximum number of filtered specs that are cached. The number of keys
+// depends on the requests, so the least recently used spec is discarded when the limit is exceeded.
+const maxSpecFilterCacheEntries = 100
+
+// specFilterCache caches filtered specs by SpecFilterResult.Key.
+type specFilterCache struct {
+	mu      sync.Mutex
+	source  []byte
+	entries map[string][]byte
+	// keys contains the keys of all entries from the least to the most recently used.
+	keys []string
+}
+
+// filter returns the filtered version of spec for the request. Cached entries are discarded
+// whenever the spec changes (e.g., after it has been reloaded, see WithSpecFilePath).
+func (c *specFilterCache) filter(r *http.Request, spec []byte, filter SpecFilter) ([]byte, error) {
+	result, err := filter(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot filter spec: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil || !sameBytes(c.source, spec) {
+		c.source = spec
+		c.entries = make(map[string][]byte)
+		c.keys = nil
+	}
+
+	if filtered, ok := c.entries[result.Key]; ok {
+		c.use(result.Key)
+		return filtered, nil
+	}
+
+	filtered, err := filterSpec(spec, result.Keep)
+	if err != nil {
+		return nil, err
+	}
+
+	c.entries[result.Key] = filtered
+	c.use(result.Key)
+
+	if len(c.keys) > maxSpecFilterCacheEntries {
+		delete(c.entries, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+
+	return filtered, nil
+}
+
+// use marks the entry with the given key as the most recently used one.
+func (c *specFilterCache) use(key string) {
+	for idx, k := range c.keys {
+		if k == key {
+			c.keys = append(c.keys[:idx], c.keys[idx+1:]...)
+			break
+		}
+	}
+
+	c.keys = append(c.keys, key)
+}
+
+// sameBytes reports whether both slices share the same underlying array. Specs are never modified
+// in place, so this is enough to detect whether a spec has changed.
+func sameBytes(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+func filterSpec(data []byte, keep func(op Operation) bool) ([]byte, error) {
+	if keep == nil {
+		return data, nil
+	}
+
+	spec, err := parseSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	removeOperations(spec, keep)
+
+	return marshalSpec(spec)
+}
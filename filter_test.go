@@ -0,0 +1,203 @@
+package go_swagger_ui
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+const filterTestSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+tags:
+  - name: pets
+  - name: admin
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+  /users:
+    x-audience: internal
+    get:
+      tags: [admin]
+      responses:
+        "200":
+          description: ok
+  /reports:
+    get:
+      tags: [admin]
+      x-audience: [partner, internal]
+      responses:
+        "200":
+          description: ok
+`
+
+func TestSpecFilters(t *testing.T) {
+	spec, err := yamlOrJSONToJSON([]byte(filterTestSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := func(values ...string) func(r *http.Request) []string {
+		return func(r *http.Request) []string { return values }
+	}
+
+	tests := []struct {
+		name          string
+		filter        SpecFilter
+		expectedPaths []string
+		expectedTags  []string
+	}{
+		{"tags", FilterByTags(values("pets")), []string{"/pets"}, []string{"pets"}},
+		{"multiple tags", FilterByTags(values("pets", "admin")), []string{"/pets", "/reports", "/users"}, []string{"pets", "admin"}},
+		{"no tags", FilterByTags(values()), nil, nil},
+		{"extension on path item", FilterByExtension("x-audience", values("internal")), []string{"/pets", "/reports", "/users"}, []string{"pets", "admin"}},
+		{"extension list", FilterByExtension("x-audience", values("partner")), []string{"/pets", "/reports"}, []string{"pets", "admin"}},
+		{"no extension values", FilterByExtension("x-audience", values()), []string{"/pets"}, []string{"pets"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.filter(httptest.NewRequest(http.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			filtered, err := filterSpec(spec, result.Keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := parseSpec(filtered)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			paths, _ := parsed["paths"].(map[string]any)
+			if keys := sortedKeys(paths); !slices.Equal(keys, tt.expectedPaths) {
+				t.Errorf("expected paths %v, got %v", tt.expectedPaths, keys)
+			}
+
+			var tags []string
+			list, _ := parsed["tags"].([]any)
+			for _, tag := range list {
+				tags = append(tags, tag.(map[string]any)["name"].(string))
+			}
+			if !slices.Equal(tags, tt.expectedTags) {
+				t.Errorf("expected tags %v, got %v", tt.expectedTags, tags)
+			}
+		})
+	}
+}
+
+func TestSpecFilterKeys(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	key := func(filter SpecFilter) string {
+		result, err := filter(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.Key
+	}
+
+	tests := []struct {
+		name string
+		a, b SpecFilter
+	}{
+		{
+			name: "tags containing separators",
+			a:    FilterByTags(func(r *http.Request) []string { return []string{"a,b"} }),
+			b:    FilterByTags(func(r *http.Request) []string { return []string{"a", "b"} }),
+		},
+		{
+			name: "extension names containing separators",
+			a:    FilterByExtension("x-a", func(r *http.Request) []string { return []string{"b:c"} }),
+			b:    FilterByExtension("x-a:b", func(r *http.Request) []string { return []string{"c"} }),
+		},
+		{
+			name: "tags and extension",
+			a:    FilterByTags(func(r *http.Request) []string { return []string{"a"} }),
+			b:    FilterByExtension("tags", func(r *http.Request) []string { return []string{"a"} }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if a, b := key(tt.a), key(tt.b); a == b {
+				t.Errorf("expected different keys, got %q for both", a)
+			}
+		})
+	}
+
+	sameA := FilterByTags(func(r *http.Request) []string { return []string{"b", "a", "a"} })
+	sameB := FilterByTags(func(r *http.Request) []string { return []string{"a", "b"} })
+	if a, b := key(sameA), key(sameB); a != b {
+		t.Errorf("expected the same key for the same set of tags, got %q and %q", a, b)
+	}
+}
+
+func TestSpecFilterCacheSeparatesKeys(t *testing.T) {
+	spec, err := yamlOrJSONToJSON([]byte(filterTestSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cache specFilterCache
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	pets, err := cache.filter(req, spec, FilterByTags(func(r *http.Request) []string { return []string{"pets"} }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin, err := cache.filter(req, spec, FilterByTags(func(r *http.Request) []string { return []string{"admin"} }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(pets) == string(admin) {
+		t.Error("expected different specs for different tags")
+	}
+}
+
+func TestSpecFilterCacheIsLimited(t *testing.T) {
+	spec, err := yamlOrJSONToJSON([]byte(filterTestSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cache specFilterCache
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	filter := func(tag string) SpecFilter {
+		return FilterByTags(func(r *http.Request) []string { return []string{tag} })
+	}
+
+	for idx := 0; idx < maxSpecFilterCacheEntries+10; idx++ {
+		if _, err := cache.filter(req, spec, filter(fmt.Sprint(idx))); err != nil {
+			t.Fatal(err)
+		}
+
+		// The first entry is used on every request, so it is never discarded.
+		if _, err := cache.filter(req, spec, filter("0")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(cache.entries) != maxSpecFilterCacheEntries || len(cache.keys) != maxSpecFilterCacheEntries {
+		t.Errorf("expected %d cached entries, got %d", maxSpecFilterCacheEntries, len(cache.entries))
+	}
+	if _, ok := cache.entries[`tags:["0"]`]; !ok {
+		t.Error("expected the most recently used entry to be cached")
+	}
+	if _, ok := cache.entries[`tags:["1"]`]; ok {
+		t.Error("expected the least recently used entry to be discarded")
+	}
+}
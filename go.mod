@@ -2,4 +2,7 @@ module github.com/alexliesenfeld/go-swagger-ui
 
 go 1.18
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	golang.org/x/crypto v0.17.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -13,7 +13,6 @@ import (
 	"log/slog"
 	"mime"
 	"net/http"
-	"os"
 	"path"
 	"path/filepath"
 	"strconv"
@@ -29,6 +28,7 @@ var templatesFS embed.FS
 var tplOverrides = map[string]*template.Template{
 	"index.html":             template.Must(template.ParseFS(templatesFS, "swagger-ui/templates/index.html")),
 	"swagger-initializer.js": template.Must(template.ParseFS(templatesFS, "swagger-ui/templates/swagger-initializer.js")),
+	"oauth2-redirect.html":   template.Must(template.ParseFS(templatesFS, "swagger-ui/templates/oauth2-redirect.html")),
 }
 
 var allFilePaths = Must(walkFS("swagger-ui/dist/", &swaggerUIFS, "."))
@@ -42,16 +42,67 @@ func NewHandler(opts ...Option) http.HandlerFunc {
 		opts[idx](&cfg)
 	}
 
+	if cfg.specFS != nil {
+		cfg.spec = Must(loadSpecFile(&cfg))
+	}
+
+	if len(cfg.mergeSources) > 0 {
+		cfg.spec = Must(mergeConfiguredSpecs(&cfg))
+	}
+
 	if len(cfg.spec) > 0 {
-		cfg.spec = Must(yamlOrJSONToJSON(cfg.spec))
+		cfg.spec = Must(prepareSpec(cfg.spec, &cfg))
+	}
+
+	// Endpoints of optional features are served on reserved file names next to the Swagger UI files.
+	cfg.endpoints = make(map[string]http.Handler)
+	if cfg.tryItOutProxy.IsSet {
+		cfg.endpoints[proxyEndpoint] = newTryItOutProxy(&cfg)
+	}
+	if cfg.localValidator.IsSet {
+		cfg.endpoints[validatorEndpoint] = newLocalValidator(&cfg)
+		cfg.endpoints[specEndpoint] = specHandler(&cfg)
+	}
+
+	// Endpoints with sub-paths are served on reserved path prefixes.
+	prefixEndpoints := make(map[string]http.Handler)
+	if cfg.mockServer.IsSet {
+		prefixEndpoints[mockEndpoint] = newMockHandler(cfg.currentSpec, cfg.mockServer.Value)
+	}
+	if servesSpecFiles(&cfg) {
+		prefixEndpoints[filesEndpoint] = &specFilesHandler{cfg: &cfg}
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
+		if cfg.authorizer != nil && !authorize(w, r, cfg.authorizer) {
+			return
+		}
+
+		for prefix, endpoint := range prefixEndpoints {
+			if subPath, ok := endpointSubPath(r.URL.Path, prefix); ok {
+				r = r.Clone(r.Context())
+				r.URL.Path, r.URL.RawPath = subPath, ""
+				endpoint.ServeHTTP(w, r)
+				return
+			}
+		}
+
 		fileName := strings.TrimPrefix(strings.TrimSpace(path.Base(r.URL.Path)), "/")
 		if fileName == "" {
 			fileName = "index.html"
 		}
 
+		// Endpoints may also serve sub-paths (e.g., "_validator/debug").
+		endpoint, ok := cfg.endpoints[fileName]
+		if !ok {
+			endpoint, ok = cfg.endpoints[path.Base(path.Dir(r.URL.Path))]
+		}
+
+		if ok {
+			endpoint.ServeHTTP(w, r)
+			return
+		}
+
 		// Always serve "index.html" if a file is being asked for does not exist.
 		// These cases are usually caused by http.Handler instances that are mounted on URL paths
 		// that do not end with a slash (e.g., https://example.com/hello, in which case the
@@ -63,14 +114,45 @@ func NewHandler(opts ...Option) http.HandlerFunc {
 		// We reload the spec file only for the CLI. In a normal production HTTP mode
 		// "specFilePath" should be unset and not used at all. See WithSpecFilePath.
 		if cfg.specFilePath != "" && fileName == "index.html" {
-			newSpecContent, err := readSpecFile(cfg.specFilePath)
+			newSpecContent, err := loadSpecFile(&cfg)
 			if err != nil {
 				slog.Error("error reading Swagger UI file", "err", err.Error())
 				sendError(w, err)
 				return
 			}
 
-			cfg.spec = newSpecContent
+			newSpec, err := prepareSpec(newSpecContent, &cfg)
+			if err != nil {
+				slog.Error("error preparing Swagger UI file", "err", err.Error())
+				sendError(w, err)
+				return
+			}
+			cfg.setSpec(newSpec)
+		}
+
+		// The spec is only embedded into the initializer script, so it only needs
+		// to be prepared for the requesting user in this case.
+		var spec []byte
+		if fileName == "swagger-initializer.js" && !loadsSpecFromURL(&cfg) {
+			var err error
+			if spec, err = specForRequest(r, &cfg); err != nil {
+				slog.Error("failed to prepare spec", "err", err.Error())
+				sendError(w, err)
+				return
+			}
+		}
+
+		// A new nonce is generated for every HTML response, so that the Content-Security-Policy
+		// header only allows the scripts and stylesheets we reference (including the inline
+		// script of the OAuth2 redirect page).
+		var nonce string
+		if cfg.securityHeaders.Value.ContentSecurityPolicy && (fileName == "index.html" || fileName == "oauth2-redirect.html") {
+			var err error
+			if nonce, err = newNonce(); err != nil {
+				slog.Error("failed to generate nonce", "err", err.Error())
+				sendError(w, err)
+				return
+			}
 		}
 
 		// We either load the requested file from the embed filesystem directly or rendering
@@ -78,7 +160,7 @@ func NewHandler(opts ...Option) http.HandlerFunc {
 		var responseBody []byte
 		if tpl, ok := tplOverrides[fileName]; ok {
 			var buf bytes.Buffer
-			if err := replaceVars(&buf, tpl, &cfg); err != nil {
+			if err := replaceVars(&buf, tpl, &cfg, spec, nonce); err != nil {
 				slog.Error("failed to use Swagger UI template", "err", err.Error())
 				sendError(w, err)
 				return
@@ -94,6 +176,12 @@ func NewHandler(opts ...Option) http.HandlerFunc {
 			}
 		}
 
+		// Filtered or rewritten specs may differ per request and must not be cached by shared caches.
+		if (cfg.specFilter != nil || cfg.serverRewrite.IsSet) && fileName == "swagger-initializer.js" {
+			w.Header().Set("Cache-Control", "private, no-cache")
+		}
+
+		setSecurityHeaders(w, &cfg, nonce)
 		w.Header().Set("Content-Type", getContentType(fileName, responseBody))
 		w.Write(responseBody)
 	}
@@ -110,23 +198,33 @@ func sendError(w http.ResponseWriter, err error) {
 	}
 }
 
-func replaceVars(w io.Writer, tpl *template.Template, cfg *uiConfig) error {
+func replaceVars(w io.Writer, tpl *template.Template, cfg *uiConfig, spec []byte, nonce string) error {
 	urlsAsBase64EncodedJSON, err := marshalObject(cfg.urls)
 	if err != nil {
 		return fmt.Errorf("cannot marshal URLs: %w", err)
 	}
 
+	var oauth2AsBase64EncodedJSON string
+	if cfg.oauth2.IsSet {
+		oauth2AsBase64EncodedJSON, err = marshalObject(cfg.oauth2.Value)
+		if err != nil {
+			return fmt.Errorf("cannot marshal OAuth2 settings: %w", err)
+		}
+	}
+
 	return tpl.Execute(w, struct {
 		BasePath, Spec, URL, HTMLTitle, DocExpansion, DefaultModelExpandDepth, DefaultModelsExpandDepth,
 		DefaultModelRendering, QueryConfigEnabled, SupportedSubmitMethods, DeepLinking,
 		ShowMutatedRequest, ShowExtensions, ShowCommonExtensions, Filter, FilterString,
 		DisplayOperationId, TryItOutEnabled, DisplayRequestDuration, PersistAuthorization, WithCredentials,
-		OAuth2RedirectUrl, Layout, ValidatorURL, MaxDisplayedTags, PrimaryURL, ConfigURL, URLs string
+		OAuth2RedirectUrl, Layout, ValidatorURL, MaxDisplayedTags, PrimaryURL, ConfigURL, URLs, Nonce,
+		ProxyURL, OAuth2 string
+		CustomCSS template.CSS
 	}{
 		BasePath:                 cfg.basePath,
 		ConfigURL:                fromStringConfigValue(cfg.configURL),
-		Spec:                     strings.TrimSpace(base64.StdEncoding.EncodeToString(cfg.spec)),
-		URL:                      fromStringConfigValue(cfg.url),
+		Spec:                     strings.TrimSpace(base64.StdEncoding.EncodeToString(spec)),
+		URL:                      specURL(cfg),
 		HTMLTitle:                cfg.htmlTitle,
 		DocExpansion:             fromDocExpansionConfigValue(cfg.docExpansion),
 		DefaultModelExpandDepth:  fromIntConfigValue(cfg.defaultModelExpandDepth),
@@ -146,14 +244,163 @@ func replaceVars(w io.Writer, tpl *template.Template, cfg *uiConfig) error {
 		PersistAuthorization:     fromBoolConfigValue(cfg.persistAuthorization),
 		WithCredentials:          fromBoolConfigValue(cfg.withCredentials),
 		OAuth2RedirectUrl:        fromStringConfigValue(cfg.oauth2RedirectUrl),
-		Layout:                   fromStringConfigValue(cfg.oauth2RedirectUrl),
-		ValidatorURL:             fromStringConfigValue(cfg.validatorUrl),
+		Layout:                   fromStringConfigValue(cfg.layout),
+		ValidatorURL:             validatorURL(cfg),
 		MaxDisplayedTags:         fromIntConfigValue(cfg.maxDisplayedTags),
 		PrimaryURL:               fromStringConfigValue(cfg.urlsPrimary),
 		URLs:                     urlsAsBase64EncodedJSON,
+		Nonce:                    nonce,
+		ProxyURL:                 proxyURL(cfg),
+		OAuth2:                   oauth2AsBase64EncodedJSON,
+		CustomCSS:                template.CSS(cfg.customCSS),
 	})
 }
 
+// prepareSpec converts the spec document to JSON (and to OpenAPI 3, if enabled), applies all
+// configured transformations and points the servers of the spec to the mock server, if enabled.
+// It is called once for every spec that is loaded.
+func prepareSpec(data []byte, cfg *uiConfig) ([]byte, error) {
+	// The original document is validated, so that diagnostics refer to its lines and columns.
+	// Bundled and merged specs are built by the handler, so their lines and columns would
+	// not refer to any file.
+	if cfg.specValidation {
+		validate := ValidateSpec
+		if isBuiltSpec(cfg) {
+			validate = validateSpec
+		}
+
+		diagnostics, err := validate(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkDiagnostics(diagnostics); err != nil {
+			return nil, err
+		}
+	}
+
+	spec, err := yamlOrJSONToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.openAPI3Conversion {
+		if spec, err = convertSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec, err = transformSpec(spec, cfg.specTransforms); err != nil {
+		return nil, err
+	}
+
+	if cfg.mockServer.IsSet {
+		return useMockServer(spec, cfg)
+	}
+
+	return spec, nil
+}
+
+// isBuiltSpec reports whether the spec is built by the handler from one or more documents
+// (i.e., bundled or merged) instead of being used as is.
+func isBuiltSpec(cfg *uiConfig) bool {
+	return len(cfg.mergeSources) > 0 || ((cfg.specFS != nil || cfg.specFilePath != "") && !cfg.specFiles)
+}
+
+// proxyURL returns the URL of the "Try it out" proxy relative to the index.html
+// page or an empty string if the proxy is disabled.
+func proxyURL(cfg *uiConfig) string {
+	if !cfg.tryItOutProxy.IsSet {
+		return ""
+	}
+
+	return cfg.basePath + proxyEndpoint
+}
+
+// loadsSpecFromURL reports whether Swagger UI loads a spec set with WithSpec, WithSpecFS or
+// WithSpecFilePath from an endpoint of the handler instead of using the spec embedded into the
+// initializer script. Swagger UI only shows the validator badge for specs loaded from a URL and
+// resolves relative references against the URL of the spec.
+func loadsSpecFromURL(cfg *uiConfig) bool {
+	return servesSpecFiles(cfg) || cfg.localValidator.IsSet
+}
+
+// specURL returns the URL Swagger UI loads the spec from (see loadsSpecFromURL).
+func specURL(cfg *uiConfig) string {
+	switch {
+	case servesSpecFiles(cfg):
+		return specFilesURL(cfg)
+	case cfg.localValidator.IsSet && len(cfg.currentSpec()) > 0:
+		return cfg.basePath + specEndpoint
+	default:
+		return fromStringConfigValue(cfg.url)
+	}
+}
+
+// endpointSubPath returns the sub-path of a request to an endpoint that is served on a path
+// prefix (e.g., "/pets" for "/swagger-ui/_mock/pets") or false, if the request is not sent
+// to the endpoint.
+func endpointSubPath(urlPath, endpoint string) (string, bool) {
+	if idx := strings.Index(urlPath, "/"+endpoint+"/"); idx >= 0 {
+		return urlPath[idx+len(endpoint)+1:], true
+	}
+
+	if strings.HasSuffix(urlPath, "/"+endpoint) {
+		return "/", true
+	}
+
+	return "", false
+}
+
+// specHandler serves the spec that is prepared for the requesting user as JSON.
+// A spec file set with WithSpecFilePath has already been reloaded with index.html.
+func specHandler(cfg *uiConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spec, err := specForRequest(r, cfg)
+		if err != nil {
+			slog.Error("failed to prepare spec", "err", err.Error())
+			sendError(w, err)
+			return
+		}
+
+		if len(spec) == 0 {
+			sendStatus(w, http.StatusNotFound)
+			return
+		}
+
+		if cfg.specFilter != nil || cfg.serverRewrite.IsSet {
+			w.Header().Set("Cache-Control", "private, no-cache")
+		}
+
+		setSecurityHeaders(w, cfg, "")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}
+}
+
+// specForRequest returns the spec that is embedded into Swagger UI for the given request.
+func specForRequest(r *http.Request, cfg *uiConfig) ([]byte, error) {
+	spec := cfg.currentSpec()
+	if len(spec) == 0 {
+		return spec, nil
+	}
+
+	var err error
+	if cfg.specFilter != nil {
+		if spec, err = cfg.specFilterCache.filter(r, spec, cfg.specFilter); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.serverRewrite.IsSet {
+		if spec, err = rewriteServers(r, spec, cfg.serverRewrite.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return spec, nil
+}
+
 func fromStringConfigValue(v configValue[string]) string {
 	if v.IsSet {
 		return strings.ReplaceAll(v.Value, "\n", "\\n")
@@ -194,21 +441,6 @@ func fromBoolConfigValue(v configValue[bool]) string {
 	return ""
 }
 
-func readSpecFile(path string) ([]byte, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
-	}
-	defer file.Close()
-
-	data, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("error reading spec file: %w", err)
-	}
-
-	return data, nil
-}
-
 func getContentType(fileName string, content []byte) string {
 	contentType := mime.TypeByExtension(filepath.Ext(fileName))
 	if contentType == "" {
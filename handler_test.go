@@ -0,0 +1,92 @@
+package go_swagger_ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+servers:
+  - url: https://api.example.com
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              example: [{"name": "Rex"}]
+`
+
+// TestSpecFileReloadIsSafeForConcurrentUse reloads a spec file set with WithSpecFilePath while the
+// endpoints that read the spec are requested. Run with -race to detect data races.
+func TestSpecFileReloadIsSafeForConcurrentUse(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte(testSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewHandler(
+		WithSpecFilePath(specPath),
+		WithTryItOutProxy(TryItOutProxy{}),
+		WithMockServer(MockServer{}),
+		WithLocalValidator(LocalValidator{}),
+	)
+
+	paths := []string{
+		"/index.html",
+		"/swagger-initializer.js",
+		"/_spec.json",
+		"/_mock/pets",
+		"/_proxy?url=" + "https%3A%2F%2Fnot-allowed.example.com%2F",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, p := range paths {
+			wg.Add(1)
+			go func(p string) {
+				defer wg.Done()
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, p, nil))
+			}(p)
+		}
+	}
+	wg.Wait()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/_mock/pets", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected mock response status 200, got %d", rec.Code)
+	}
+}
+
+// TestInitializerUsesConfiguredLayout is a regression test for the layout, which used to be set to the
+// OAuth2 redirect URL.
+func TestInitializerUsesConfiguredLayout(t *testing.T) {
+	handler := NewHandler(
+		WithSpec([]byte(testSpec)),
+		WithLayout(LayoutStandaloneLayout),
+		WithOauth2RedirectUrl("https://docs.example.com/oauth2-redirect.html"),
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger-initializer.js", nil))
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "layout: blankToUndefined('StandaloneLayout')") {
+		t.Errorf("expected layout StandaloneLayout in initializer script:\n%s", body)
+	}
+	if strings.Count(body, "https://docs.example.com/oauth2-redirect.html") != 1 {
+		t.Error("expected the OAuth2 redirect URL to be used only for oauth2RedirectUrl")
+	}
+}
@@ -0,0 +1,477 @@
+package go_swagger_ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// redactedValue replaces the values of redacted headers and query parameters in recorded entries.
+const redactedValue = "[REDACTED]"
+
+// DefaultHARRedactions are the headers and query parameters that are redacted by HARRecorder by default.
+var DefaultHARRedactions = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+	"api_key",
+	"access_token",
+}
+
+// HARRecorder is an http.RoundTripper that records requests and responses in the HTTP Archive (HAR) 1.2
+// format, which can be attached to bug reports and imported into browser developer tools and other
+// HTTP clients to replay the requests. Use it as TryItOutProxy.Transport to record "Try it out" requests:
+//
+//	recorder := &swaggerui.HARRecorder{FileName: "try-it-out.har"}
+//	swaggerui.WithTryItOutProxy(swaggerui.TryItOutProxy{Transport: recorder})
+//
+// Request bodies are read into memory before they are sent. The zero value is ready to use.
+type HARRecorder struct {
+	// Transport sends the requests. Default is http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// FileName is the path of a file the archive is written to. Every recorded entry is appended to the
+	// file, which is rewritten with the last MaxEntries entries once it holds twice as many.
+	// If empty, entries are only kept in memory (see WriteHAR).
+	FileName string
+
+	// Redact is a list of header and query parameter names (case-insensitive) whose values are replaced
+	// with "[REDACTED]" in recorded entries. Requests are sent unchanged. Default is DefaultHARRedactions.
+	// Set it to an empty, non-nil slice to record all values.
+	Redact []string
+
+	// MaxBodySize is the maximum number of bytes of a request or response body that is recorded.
+	// Longer bodies are truncated. Default is 1 MiB.
+	MaxBodySize int64
+
+	// MaxEntries is the maximum number of recorded entries. When it is reached, the oldest entry is
+	// removed. Default is 1000.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries []harEntry
+
+	// fileEntries is the number of entries in the file and fileSize its size in bytes.
+	// If fileEntries is 0, the file is rewritten when the next entry is recorded.
+	fileEntries int
+	fileSize    int64
+}
+
+// harEntriesSuffix follows the last entry of a document written by HARRecorder.marshal.
+// Entries are appended to the file by overwriting it.
+const harEntriesSuffix = "\n    ]\n  }\n}"
+
+type harLog struct {
+	Log struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+	Comment     string         `json:"comment,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// RoundTrip sends the request with the transport and records it together with its response. The entry
+// is recorded when the response body is closed. Failed requests are not recorded.
+func (h *HARRecorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	transport := h.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	var requestBody []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		requestBody, err = io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read request body: %w", err)
+		}
+
+		// A RoundTripper must not modify the request it receives.
+		r = r.Clone(r.Context())
+		r.Body = io.NopCloser(bytes.NewReader(requestBody))
+		r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(requestBody)), nil }
+	}
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	wait := time.Since(start)
+
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Request:         h.request(r, requestBody),
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode))),
+			HTTPVersion: resp.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     h.headers(resp.Header),
+			RedirectURL: resp.Header.Get("Location"),
+			HeadersSize: -1,
+		},
+		Timings: harTimings{Wait: milliseconds(wait)},
+	}
+
+	resp.Body = &harBodyRecorder{
+		ReadCloser: resp.Body,
+		maxSize:    h.maxBodySize(),
+		done: func(body []byte, size int64, truncated bool) {
+			entry.Response.BodySize = size
+			entry.Response.Content = harContent{Size: size, MimeType: resp.Header.Get("Content-Type")}
+			entry.Response.Content.Text, entry.Response.Content.Encoding = bodyText(body)
+			if truncated {
+				entry.Response.Content.Comment = fmt.Sprintf("truncated to %d bytes", len(body))
+			}
+
+			total := time.Since(start)
+			entry.Timings.Receive = milliseconds(total - wait)
+			entry.Time = milliseconds(total)
+			h.add(entry)
+		},
+	}
+
+	return resp, nil
+}
+
+// WriteHAR writes all recorded entries as HAR document.
+func (h *HARRecorder) WriteHAR(w io.Writer) error {
+	h.mu.Lock()
+	data, err := h.marshal()
+	h.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// Len returns the number of recorded entries.
+func (h *HARRecorder) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.entries)
+}
+
+// Reset removes all recorded entries.
+func (h *HARRecorder) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = nil
+	h.fileEntries = 0
+}
+
+func (h *HARRecorder) add(entry harEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	maxEntries := h.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxEntries {
+		h.entries = h.entries[len(h.entries)-maxEntries:]
+	}
+
+	if h.FileName == "" {
+		return
+	}
+
+	var err error
+	if h.fileEntries == 0 || h.fileEntries >= 2*maxEntries {
+		err = h.writeFile()
+	} else {
+		err = h.appendToFile(entry)
+	}
+	if err != nil {
+		h.fileEntries = 0
+		slog.Error("cannot write HAR file", "file", h.FileName, "err", err.Error())
+	}
+}
+
+// writeFile writes all entries in memory to the file.
+func (h *HARRecorder) writeFile() error {
+	data, err := h.marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(h.FileName, data, 0o600); err != nil {
+		return err
+	}
+
+	h.fileEntries = len(h.entries)
+	h.fileSize = int64(len(data))
+	return nil
+}
+
+// appendToFile appends an entry to the file written by writeFile without rewriting the other entries.
+func (h *HARRecorder) appendToFile(entry harEntry) error {
+	data, err := json.MarshalIndent(entry, "      ", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal HAR entry: %w", err)
+	}
+
+	f, err := os.OpenFile(h.FileName, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data = append(append([]byte(",\n      "), data...), harEntriesSuffix...)
+	if _, err := f.WriteAt(data, h.fileSize-int64(len(harEntriesSuffix))); err != nil {
+		return err
+	}
+
+	h.fileEntries++
+	h.fileSize += int64(len(data) - len(harEntriesSuffix))
+	return f.Close()
+}
+
+func (h *HARRecorder) marshal() ([]byte, error) {
+	var doc harLog
+	doc.Log.Version = "1.2"
+	doc.Log.Creator = harCreator{Name: "go-swagger-ui", Version: "1.0"}
+	doc.Log.Entries = h.entries
+	if doc.Log.Entries == nil {
+		doc.Log.Entries = []harEntry{}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal HAR: %w", err)
+	}
+
+	return data, nil
+}
+
+func (h *HARRecorder) request(r *http.Request, body []byte) harRequest {
+	u := *r.URL
+	query := u.Query()
+	for name, values := range query {
+		if h.isRedacted(name) {
+			for idx := range values {
+				values[idx] = redactedValue
+			}
+		}
+	}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	req := harRequest{
+		Method:      r.Method,
+		URL:         u.String(),
+		HTTPVersion: r.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     h.headers(r.Header),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+	}
+
+	if r.Host != "" && r.Host != r.URL.Host {
+		req.Headers = append(req.Headers, harNameValue{Name: "Host", Value: r.Host})
+	}
+
+	for _, name := range sortedKeys(query) {
+		for _, value := range query[name] {
+			req.QueryString = append(req.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	if len(body) > 0 {
+		recorded := body
+		if maxSize := h.maxBodySize(); int64(len(recorded)) > maxSize {
+			recorded = recorded[:maxSize]
+		}
+
+		req.PostData = &harPostData{MimeType: r.Header.Get("Content-Type")}
+		if utf8.Valid(recorded) {
+			req.PostData.Text = string(recorded)
+		} else {
+			// HAR 1.2 has no encoding field for request bodies.
+			req.PostData.Text = base64.StdEncoding.EncodeToString(recorded)
+			req.PostData.Comment = "base64 encoded"
+		}
+		if len(recorded) < len(body) {
+			req.PostData.Comment = strings.TrimPrefix(req.PostData.Comment+", truncated", ", ")
+		}
+	}
+
+	return req
+}
+
+// headers returns the headers sorted by name with redacted values.
+func (h *HARRecorder) headers(header http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for _, name := range sortedKeys(header) {
+		for _, value := range header[name] {
+			if h.isRedacted(name) {
+				value = redactedValue
+			}
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return headers
+}
+
+func (h *HARRecorder) isRedacted(name string) bool {
+	redact := h.Redact
+	if redact == nil {
+		redact = DefaultHARRedactions
+	}
+
+	for _, redacted := range redact {
+		if strings.EqualFold(redacted, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *HARRecorder) maxBodySize() int64 {
+	if h.MaxBodySize <= 0 {
+		return 1 << 20
+	}
+
+	return h.MaxBodySize
+}
+
+// harBodyRecorder records a response body while it is read and calls done once when the body
+// is read completely or closed.
+type harBodyRecorder struct {
+	io.ReadCloser
+	maxSize   int64
+	body      []byte
+	size      int64
+	truncated bool
+	once      sync.Once
+	done      func(body []byte, size int64, truncated bool)
+}
+
+func (b *harBodyRecorder) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.size += int64(n)
+	if remaining := b.maxSize - int64(len(b.body)); remaining > 0 {
+		if int64(n) < remaining {
+			remaining = int64(n)
+		}
+		b.body = append(b.body, p[:remaining]...)
+	}
+	b.truncated = b.size > int64(len(b.body))
+
+	if err == io.EOF {
+		b.finish()
+	}
+
+	return n, err
+}
+
+func (b *harBodyRecorder) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *harBodyRecorder) finish() {
+	b.once.Do(func() { b.done(b.body, b.size, b.truncated) })
+}
+
+// bodyText returns the text and encoding of a body for HAR content. Binary bodies are base64 encoded.
+func bodyText(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,170 @@
+package go_swagger_ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// harTestRequest sends a request through the recorder and reads the response body, so that the entry is recorded.
+func harTestRequest(t *testing.T, recorder *HARRecorder, req *http.Request) {
+	t.Helper()
+
+	resp, err := (&http.Client{Transport: recorder}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func parseTestHAR(t *testing.T, data []byte) harLog {
+	t.Helper()
+
+	var doc harLog
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("cannot parse HAR %s: %v", data, err)
+	}
+
+	return doc
+}
+
+func TestHARRecorderRedactsValues(t *testing.T) {
+	var received *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Session-Id", "secret")
+		w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name             string
+		redact           []string
+		expectedRedacted []string
+	}{
+		{"default redactions", nil, []string{"Authorization", "Cookie", "Set-Cookie", "api_key", "access_token"}},
+		{"custom redactions", []string{"x-session-id", "Token"}, []string{"X-Session-Id", "token"}},
+		{"no redactions", []string{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &HARRecorder{Redact: tt.redact}
+
+			req, _ := http.NewRequest(http.MethodGet, upstream.URL+"/pets?api_key=secret&access_token=secret&token=secret&limit=10", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			req.Header.Set("Cookie", "session=secret")
+			req.Header.Set("X-Session-Id", "secret")
+			harTestRequest(t, recorder, req)
+
+			if received.Header.Get("Authorization") != "Bearer secret" || received.Header.Get("X-Session-Id") != "secret" {
+				t.Errorf("expected the upstream to receive the real header values, got %v", received.Header)
+			}
+			if received.URL.RawQuery != "api_key=secret&access_token=secret&token=secret&limit=10" {
+				t.Errorf("expected the upstream to receive the real query, got %s", received.URL.RawQuery)
+			}
+
+			var buf bytes.Buffer
+			if err := recorder.WriteHAR(&buf); err != nil {
+				t.Fatal(err)
+			}
+			entry := parseTestHAR(t, buf.Bytes()).Log.Entries[0]
+
+			values := make(map[string]string)
+			for _, nameValues := range [][]harNameValue{entry.Request.Headers, entry.Request.QueryString, entry.Response.Headers} {
+				for _, nv := range nameValues {
+					values[nv.Name] = nv.Value
+				}
+			}
+
+			redacted := make(map[string]bool)
+			for _, name := range tt.expectedRedacted {
+				redacted[name] = true
+				if values[name] != redactedValue {
+					t.Errorf("expected %s to be redacted, got %q", name, values[name])
+				}
+			}
+			for _, name := range []string{"Authorization", "Cookie", "X-Session-Id", "Set-Cookie", "api_key", "access_token", "token"} {
+				if !redacted[name] && values[name] == redactedValue {
+					t.Errorf("expected %s not to be redacted", name)
+				}
+			}
+			if values["limit"] != "10" {
+				t.Errorf("expected query parameter limit to be recorded, got %q", values["limit"])
+			}
+
+			recordedURL, err := url.Parse(entry.Request.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, recorded := range recordedURL.Query() {
+				if recorded[0] != values[name] {
+					t.Errorf("expected query parameter %s of the URL to be %q, got %q", name, values[name], recorded[0])
+				}
+			}
+		})
+	}
+}
+
+func TestHARRecorderAppendsToFile(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	fileName := filepath.Join(t.TempDir(), "try-it-out.har")
+	recorder := &HARRecorder{FileName: fileName, MaxEntries: 3}
+
+	fileEntries := func() []string {
+		t.Helper()
+
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := recorder.WriteHAR(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if recorder.Len() == recorder.fileEntries && !bytes.Equal(data, buf.Bytes()) {
+			t.Errorf("expected the file to match WriteHAR, got\n%s\nexpected\n%s", data, buf.Bytes())
+		}
+
+		var paths []string
+		for _, entry := range parseTestHAR(t, data).Log.Entries {
+			paths = append(paths, entry.Response.Content.Text)
+		}
+		return paths
+	}
+
+	for idx := 1; idx <= 7; idx++ {
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%d", upstream.URL, idx), nil)
+		harTestRequest(t, recorder, req)
+
+		// Entries are appended until the file holds twice MaxEntries entries.
+		expected := idx
+		if idx == 7 {
+			expected = 3
+		}
+		if paths := fileEntries(); len(paths) != expected || paths[len(paths)-1] != fmt.Sprintf("/%d", idx) {
+			t.Fatalf("expected %d entries ending with request %d, got %v", expected, idx, paths)
+		}
+	}
+
+	recorder.Reset()
+	req, _ := http.NewRequest(http.MethodGet, upstream.URL+"/6", nil)
+	harTestRequest(t, recorder, req)
+	if paths := fileEntries(); len(paths) != 1 || paths[0] != "/6" {
+		t.Errorf("expected the file to be rewritten after Reset, got %v", paths)
+	}
+}
@@ -0,0 +1,422 @@
+package go_swagger_ui
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SeverityInfo is the severity of lint problems that are only hints.
+	SeverityInfo Severity = "info"
+
+	// SeverityOff disables a lint rule. See LintConfig.
+	SeverityOff Severity = "off"
+)
+
+// LintReporter reports a problem at the location of the given JSON pointer (e.g., "/paths/~1pets/get").
+type LintReporter func(pointer, format string, args ...any)
+
+// LintRule is a style rule that checks a spec. See LintSpec.
+type LintRule struct {
+	// Name identifies the rule (e.g., "operation-id").
+	Name string
+	// Description describes what the rule checks.
+	Description string
+	// Severity is the default severity of the problems found by the rule.
+	// Rules with severity SeverityOff must be enabled explicitly.
+	Severity Severity
+	// Check reports all problems in the parsed spec document. The spec must not be modified.
+	Check func(spec map[string]any, report LintReporter)
+}
+
+// LintConfig configures LintSpec.
+type LintConfig struct {
+	// Rules are the rules to check. Default is DefaultLintRules.
+	Rules []LintRule
+	// Severities overrides the severities of rules by rule name. SeverityOff disables a rule.
+	Severities map[string]Severity
+}
+
+// LintConfigError is returned if a LintConfig overrides the severity of an unknown rule
+// or sets an invalid severity.
+type LintConfigError struct {
+	// Rule is the name of the rule.
+	Rule string
+	// Message describes the problem.
+	Message string
+}
+
+func (e *LintConfigError) Error() string {
+	return fmt.Sprintf("lint rule %q: %s", e.Rule, e.Message)
+}
+
+// Validate returns a *LintConfigError if a severity is set for an unknown rule or is not
+// one of SeverityError, SeverityWarning, SeverityInfo or SeverityOff.
+func (c LintConfig) Validate() error {
+	rules := c.Rules
+	if rules == nil {
+		rules = DefaultLintRules()
+	}
+
+	ruleNames := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		ruleNames[rule.Name] = struct{}{}
+	}
+
+	for _, name := range sortedKeys(c.Severities) {
+		if _, ok := ruleNames[name]; !ok {
+			return &LintConfigError{Rule: name, Message: "unknown rule"}
+		}
+
+		switch c.Severities[name] {
+		case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		default:
+			return &LintConfigError{Rule: name, Message: fmt.Sprintf("invalid severity %q: must be one of error, warning, info, off", c.Severities[name])}
+		}
+	}
+
+	return nil
+}
+
+// LintSpec checks a Swagger 2.0 or OpenAPI 3 document (as YAML or JSON) against style rules,
+// such as naming conventions and documentation requirements. Unlike ValidateSpec, it does not check
+// whether the document is valid. All diagnostics contain the line and column of the problem in the
+// document. An error is returned if the document cannot be parsed or the config is invalid (see LintConfig.Validate).
+func LintSpec(spec []byte, config LintConfig) ([]Diagnostic, error) {
+	rules := config.Rules
+	if rules == nil {
+		rules = DefaultLintRules()
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	jsonSpec, err := yamlOrJSONToJSON(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseSpec(jsonSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []Diagnostic
+	for _, rule := range rules {
+		severity := rule.Severity
+		if override, ok := config.Severities[rule.Name]; ok {
+			severity = override
+		}
+
+		if severity == SeverityOff {
+			continue
+		}
+
+		rule.Check(doc, func(pointer, format string, args ...any) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: severity,
+				Rule:     rule.Name,
+				Message:  fmt.Sprintf(format, args...),
+				Pointer:  pointer,
+			})
+		})
+	}
+
+	return withPositions(spec, diagnostics), nil
+}
+
+// DefaultLintRules returns the default rules of LintSpec.
+func DefaultLintRules() []LintRule {
+	return []LintRule{
+		{
+			Name:        "info-description",
+			Description: "The API has a description.",
+			Severity:    SeverityInfo,
+			Check:       lintInfoField("description"),
+		},
+		{
+			Name:        "info-contact",
+			Description: "The API has contact information.",
+			Severity:    SeverityInfo,
+			Check:       lintInfoField("contact"),
+		},
+		{
+			Name:        "kebab-case-paths",
+			Description: "Path segments are kebab-case (e.g., \"/user-accounts/{id}\").",
+			Severity:    SeverityWarning,
+			Check:       lintKebabCasePaths,
+		},
+		{
+			Name:        "no-trailing-slash",
+			Description: "Paths do not end with a slash.",
+			Severity:    SeverityWarning,
+			Check:       lintTrailingSlash,
+		},
+		{
+			Name:        "operation-id",
+			Description: "Every operation has an operationId.",
+			Severity:    SeverityWarning,
+			Check: lintOperations(func(op Operation, value map[string]any, pointer string, report LintReporter) {
+				if op.OperationID == "" {
+					report(pointer, "operation has no operationId")
+				}
+			}),
+		},
+		{
+			Name:        "operation-description",
+			Description: "Every operation has a summary or description.",
+			Severity:    SeverityWarning,
+			Check: lintOperations(func(op Operation, value map[string]any, pointer string, report LintReporter) {
+				if value["summary"] == nil && value["description"] == nil {
+					report(pointer, "operation has no summary or description")
+				}
+			}),
+		},
+		{
+			Name:        "operation-tags",
+			Description: "Every operation has at least one tag.",
+			Severity:    SeverityWarning,
+			Check: lintOperations(func(op Operation, value map[string]any, pointer string, report LintReporter) {
+				if len(op.Tags) == 0 {
+					report(pointer, "operation has no tags")
+				}
+			}),
+		},
+		{
+			Name:        "operation-tag-defined",
+			Description: "Tags of operations are listed in the global tags.",
+			Severity:    SeverityWarning,
+			Check:       lintOperationTagsDefined,
+		},
+		{
+			Name:        "tag-description",
+			Description: "Global tags have a description.",
+			Severity:    SeverityInfo,
+			Check:       lintTagDescriptions,
+		},
+		{
+			Name:        "parameter-description",
+			Description: "Every parameter has a description.",
+			Severity:    SeverityWarning,
+			Check:       lintParameterDescriptions,
+		},
+		{
+			Name:        "success-response",
+			Description: "Every operation defines a successful (2xx or 3xx) response.",
+			Severity:    SeverityWarning,
+			Check:       lintResponses("successful (2xx or 3xx)", "2", "3"),
+		},
+		{
+			Name:        "error-responses",
+			Description: "Every operation defines an error (4xx, 5xx or default) response.",
+			Severity:    SeverityWarning,
+			Check:       lintResponses("error (4xx, 5xx or default)", "4", "5", "default"),
+		},
+		{
+			Name:        "https-servers",
+			Description: "Servers use HTTPS.",
+			Severity:    SeverityWarning,
+			Check:       lintHTTPSServers,
+		},
+		{
+			Name:        "camel-case-properties",
+			Description: "Schema properties are camelCase (e.g., \"firstName\").",
+			Severity:    SeverityOff,
+			Check:       lintCamelCaseProperties,
+		},
+	}
+}
+
+func lintInfoField(field string) func(spec map[string]any, report LintReporter) {
+	return func(spec map[string]any, report LintReporter) {
+		if lookup(spec, "info", field) == nil {
+			report("/info", "info has no %s", field)
+		}
+	}
+}
+
+// lintOperations returns a check that calls fn for every operation of the spec.
+func lintOperations(fn func(op Operation, value map[string]any, pointer string, report LintReporter)) func(spec map[string]any, report LintReporter) {
+	return func(spec map[string]any, report LintReporter) {
+		forEachOperation(spec, func(op Operation, value map[string]any) bool {
+			fn(op, value, "/paths/"+escapeJSONPointer(op.Path)+"/"+op.Method, report)
+			return true
+		})
+	}
+}
+
+var kebabCaseSegmentPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*(\.[a-z0-9]+)?$`)
+
+func lintKebabCasePaths(spec map[string]any, report LintReporter) {
+	paths, _ := spec["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+			// Path parameters are not checked, because their names are chosen by the parameter style.
+			if segment == "" || pathParamPattern.MatchString(segment) {
+				continue
+			}
+
+			if !kebabCaseSegmentPattern.MatchString(segment) {
+				report("/paths/"+escapeJSONPointer(path), "path segment %q is not kebab-case", segment)
+				break
+			}
+		}
+	}
+}
+
+func lintTrailingSlash(spec map[string]any, report LintReporter) {
+	paths, _ := spec["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		if path != "/" && strings.HasSuffix(path, "/") {
+			report("/paths/"+escapeJSONPointer(path), "path %q ends with a slash", path)
+		}
+	}
+}
+
+func lintOperationTagsDefined(spec map[string]any, report LintReporter) {
+	defined := make(map[string]struct{})
+	tags, _ := spec["tags"].([]any)
+	for _, tag := range tags {
+		if name, ok := lookup(tag, "name").(string); ok {
+			defined[name] = struct{}{}
+		}
+	}
+
+	lintOperations(func(op Operation, value map[string]any, pointer string, report LintReporter) {
+		for idx, tag := range op.Tags {
+			if _, ok := defined[tag]; !ok {
+				report(fmt.Sprintf("%s/tags/%d", pointer, idx), "tag %q is not listed in the global tags", tag)
+			}
+		}
+	})(spec, report)
+}
+
+func lintTagDescriptions(spec map[string]any, report LintReporter) {
+	tags, _ := spec["tags"].([]any)
+	for idx, tag := range tags {
+		if lookup(tag, "description") == nil {
+			report(fmt.Sprintf("/tags/%d", idx), "tag %v has no description", lookup(tag, "name"))
+		}
+	}
+}
+
+func lintParameterDescriptions(spec map[string]any, report LintReporter) {
+	check := func(pointer string, params any) {
+		list, _ := params.([]any)
+		for idx, param := range list {
+			paramObj := deref(spec, param)
+			if paramObj != nil && paramObj["description"] == nil {
+				report(fmt.Sprintf("%s/parameters/%d", pointer, idx), "parameter %v has no description", paramObj["name"])
+			}
+		}
+	}
+
+	paths, _ := spec["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		pointer := "/paths/" + escapeJSONPointer(path)
+		pathItem, _ := paths[path].(map[string]any)
+		check(pointer, pathItem["parameters"])
+
+		for _, method := range httpMethods {
+			check(pointer+"/"+method, lookup(pathItem, method, "parameters"))
+		}
+	}
+}
+
+// lintResponses returns a check that requires every operation to define a response
+// with a status code starting with one of the given prefixes.
+func lintResponses(kind string, prefixes ...string) func(spec map[string]any, report LintReporter) {
+	return lintOperations(func(op Operation, value map[string]any, pointer string, report LintReporter) {
+		responses, _ := value["responses"].(map[string]any)
+		for code := range responses {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(code, prefix) {
+					return
+				}
+			}
+		}
+
+		report(pointer, "operation defines no %s response", kind)
+	})
+}
+
+func lintHTTPSServers(spec map[string]any, report LintReporter) {
+	if isSwagger2(spec) {
+		schemes, _ := spec["schemes"].([]any)
+		for idx, scheme := range schemes {
+			if scheme == "http" || scheme == "ws" {
+				report(fmt.Sprintf("/schemes/%d", idx), "scheme %v is not encrypted", scheme)
+			}
+		}
+		return
+	}
+
+	servers, _ := spec["servers"].([]any)
+	for idx, server := range servers {
+		serverURL, _ := lookup(server, "url").(string)
+		if strings.HasPrefix(serverURL, "http://") && !isLocalURL(serverURL) {
+			report(fmt.Sprintf("/servers/%d/url", idx), "server %s does not use HTTPS", serverURL)
+		}
+	}
+}
+
+// isLocalURL reports whether an URL points to the local machine, which does not require HTTPS.
+func isLocalURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+var camelCasePattern = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+func lintCamelCaseProperties(spec map[string]any, report LintReporter) {
+	sections := componentSectionsOf(spec)
+	for _, section := range sortedKeys(sections) {
+		if section != "#/components/schemas" && section != "#/definitions" {
+			continue
+		}
+
+		schemas := sections[section]
+		for _, name := range sortedKeys(schemas) {
+			lintSchemaProperties(strings.TrimPrefix(section, "#")+"/"+escapeJSONPointer(name), schemas[name], report)
+		}
+	}
+}
+
+// lintSchemaProperties reports property names that are not camelCase in a schema and its sub-schemas.
+func lintSchemaProperties(pointer string, schema any, report LintReporter) {
+	schemaObj, ok := schema.(map[string]any)
+	if !ok {
+		return
+	}
+
+	properties, _ := schemaObj["properties"].(map[string]any)
+	for _, name := range sortedKeys(properties) {
+		propertyPointer := pointer + "/properties/" + escapeJSONPointer(name)
+		if !camelCasePattern.MatchString(name) {
+			report(propertyPointer, "property %q is not camelCase", name)
+		}
+		lintSchemaProperties(propertyPointer, properties[name], report)
+	}
+
+	lintSchemaProperties(pointer+"/items", schemaObj["items"], report)
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		subSchemas, _ := schemaObj[key].([]any)
+		for idx, subSchema := range subSchemas {
+			lintSchemaProperties(fmt.Sprintf("%s/%s/%d", pointer, key, idx), subSchema, report)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package go_swagger_ui
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// lintTestRule returns the default rule with the given name, enabled with its default severity
+// or SeverityWarning, if it is turned off by default.
+func lintTestRule(t *testing.T, name string) LintRule {
+	t.Helper()
+
+	for _, rule := range DefaultLintRules() {
+		if rule.Name == name {
+			if rule.Severity == SeverityOff {
+				rule.Severity = SeverityWarning
+			}
+			return rule
+		}
+	}
+
+	t.Fatalf("unknown lint rule %q", name)
+	return LintRule{}
+}
+
+func TestLintRules(t *testing.T) {
+	tests := []struct {
+		rule             string
+		spec             string
+		expectedPointers []string
+	}{
+		{"info-description", `{openapi: 3.0.3, info: {title: Pets, version: "1"}}`, []string{"/info"}},
+		{"info-description", `{openapi: 3.0.3, info: {title: Pets, version: "1", description: Pets}}`, nil},
+		{"info-contact", `{openapi: 3.0.3, info: {title: Pets, version: "1"}}`, []string{"/info"}},
+		{"info-contact", `{openapi: 3.0.3, info: {title: Pets, version: "1", contact: {name: Team}}}`, nil},
+		{"kebab-case-paths", `{openapi: 3.0.3, paths: {"/userAccounts/{id}": {}, /pet_owners: {}}}`, []string{"/paths/~1userAccounts~1{id}", "/paths/~1pet_owners"}},
+		{"kebab-case-paths", `{openapi: 3.0.3, paths: {"/user-accounts/{userId}": {}, /pets.json: {}, /: {}}}`, nil},
+		{"no-trailing-slash", `{openapi: 3.0.3, paths: {/pets/: {}}}`, []string{"/paths/~1pets~1"}},
+		{"no-trailing-slash", `{openapi: 3.0.3, paths: {/pets: {}, /: {}}}`, nil},
+		{"operation-id", `{openapi: 3.0.3, paths: {/pets: {get: {}}}}`, []string{"/paths/~1pets/get"}},
+		{"operation-id", `{openapi: 3.0.3, paths: {/pets: {get: {operationId: listPets}}}}`, nil},
+		{"operation-description", `{openapi: 3.0.3, paths: {/pets: {get: {}}}}`, []string{"/paths/~1pets/get"}},
+		{"operation-description", `{openapi: 3.0.3, paths: {/pets: {get: {summary: Pets}, post: {description: Pets}}}}`, nil},
+		{"operation-tags", `{openapi: 3.0.3, paths: {/pets: {get: {tags: []}}}}`, []string{"/paths/~1pets/get"}},
+		{"operation-tags", `{openapi: 3.0.3, paths: {/pets: {get: {tags: [pets]}}}}`, nil},
+		{"operation-tag-defined", `{openapi: 3.0.3, tags: [{name: pets}], paths: {/pets: {get: {tags: [pets, owners]}}}}`, []string{"/paths/~1pets/get/tags/1"}},
+		{"operation-tag-defined", `{openapi: 3.0.3, tags: [{name: pets}], paths: {/pets: {get: {tags: [pets]}}}}`, nil},
+		{"tag-description", `{openapi: 3.0.3, tags: [{name: pets, description: Pets}, {name: owners}]}`, []string{"/tags/1"}},
+		{"tag-description", `{openapi: 3.0.3, tags: [{name: pets, description: Pets}]}`, nil},
+		{
+			"parameter-description",
+			`{openapi: 3.0.3, paths: {/pets: {parameters: [{name: tenant, in: header}], get: {parameters: [{$ref: "#/components/parameters/Limit"}]}}}, components: {parameters: {Limit: {name: limit, in: query}}}}`,
+			[]string{"/paths/~1pets/parameters/0", "/paths/~1pets/get/parameters/0"},
+		},
+		{
+			"parameter-description",
+			`{openapi: 3.0.3, paths: {/pets: {get: {parameters: [{$ref: "#/components/parameters/Limit"}]}}}, components: {parameters: {Limit: {name: limit, in: query, description: Limit}}}}`,
+			nil,
+		},
+		{"success-response", `{openapi: 3.0.3, paths: {/pets: {get: {responses: {"404": {}, default: {}}}}}}`, []string{"/paths/~1pets/get"}},
+		{"success-response", `{openapi: 3.0.3, paths: {/pets: {get: {responses: {"200": {}}}, post: {responses: {"303": {}}}}}}`, nil},
+		{"error-responses", `{openapi: 3.0.3, paths: {/pets: {get: {responses: {"200": {}}}}}}`, []string{"/paths/~1pets/get"}},
+		{"error-responses", `{openapi: 3.0.3, paths: {/pets: {get: {responses: {"404": {}}}, post: {responses: {"500": {}}}, put: {responses: {default: {}}}}}}`, nil},
+		{
+			"https-servers",
+			`{openapi: 3.0.3, servers: [{url: "http://api.example.com"}, {url: "http://localhost.example.com"}, {url: "http://127.0.0.1.example.com"}]}`,
+			[]string{"/servers/0/url", "/servers/1/url", "/servers/2/url"},
+		},
+		{
+			"https-servers",
+			`{openapi: 3.0.3, servers: [{url: "https://api.example.com"}, {url: "http://localhost:8080"}, {url: "http://127.0.0.2/v1"}, {url: "http://[::1]:8080"}, {url: /v1}]}`,
+			nil,
+		},
+		{"https-servers", `{swagger: "2.0", schemes: [https, http, ws]}`, []string{"/schemes/1", "/schemes/2"}},
+		{"https-servers", `{swagger: "2.0", schemes: [https, wss]}`, nil},
+		{
+			"camel-case-properties",
+			`{openapi: 3.0.3, components: {schemas: {Pet: {properties: {first_name: {}, owner: {properties: {LastName: {}}}}, allOf: [{properties: {pet-id: {}}}]}}}}`,
+			[]string{
+				"/components/schemas/Pet/properties/first_name",
+				"/components/schemas/Pet/properties/owner/properties/LastName",
+				"/components/schemas/Pet/allOf/0/properties/pet-id",
+			},
+		},
+		{"camel-case-properties", `{swagger: "2.0", definitions: {Pet: {properties: {firstName: {}, items: {items: {properties: {id: {}}}}}}}}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule := lintTestRule(t, tt.rule)
+
+			diagnostics, err := LintSpec([]byte(tt.spec), LintConfig{Rules: []LintRule{rule}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var pointers []string
+			for _, d := range diagnostics {
+				if d.Rule != rule.Name || d.Severity != rule.Severity {
+					t.Errorf("expected rule %s with severity %s, got %s", rule.Name, rule.Severity, d)
+				}
+				pointers = append(pointers, d.Pointer)
+			}
+
+			if !reflect.DeepEqual(pointers, tt.expectedPointers) {
+				t.Errorf("expected problems at %v, got %v", tt.expectedPointers, diagnostics)
+			}
+		})
+	}
+}
+
+func TestLintSpecSeverities(t *testing.T) {
+	const spec = `openapi: 3.0.3
+info: {title: Pets, version: "1"}
+servers: [{url: "http://api.example.com"}]
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: Pets
+      tags: [pets]
+      responses: {"200": {description: ok}}
+components:
+  schemas:
+    Pet: {properties: {pet_id: {type: string}}}
+`
+
+	tests := []struct {
+		name       string
+		severities map[string]Severity
+		expected   map[string]Severity
+	}{
+		{
+			name: "default severities",
+			expected: map[string]Severity{
+				"info-description":      SeverityInfo,
+				"info-contact":          SeverityInfo,
+				"operation-tag-defined": SeverityWarning,
+				"error-responses":       SeverityWarning,
+				"https-servers":         SeverityWarning,
+			},
+		},
+		{
+			name: "overridden severities",
+			severities: map[string]Severity{
+				"info-description":      SeverityOff,
+				"info-contact":          SeverityOff,
+				"operation-tag-defined": SeverityInfo,
+				"https-servers":         SeverityError,
+				"camel-case-properties": SeverityWarning,
+			},
+			expected: map[string]Severity{
+				"operation-tag-defined": SeverityInfo,
+				"error-responses":       SeverityWarning,
+				"https-servers":         SeverityError,
+				"camel-case-properties": SeverityWarning,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics, err := LintSpec([]byte(spec), LintConfig{Severities: tt.severities})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			severities := make(map[string]Severity)
+			for _, d := range diagnostics {
+				if d.Line == 0 {
+					t.Errorf("expected the position of diagnostic %s", d)
+				}
+				severities[d.Rule] = d.Severity
+			}
+
+			if !reflect.DeepEqual(severities, tt.expected) {
+				t.Errorf("expected severities %v, got %v", tt.expected, severities)
+			}
+		})
+	}
+}
+
+func TestLintConfigValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        LintConfig
+		expectedError string
+	}{
+		{"default rules", LintConfig{Severities: map[string]Severity{"operation-id": SeverityError, "camel-case-properties": SeverityInfo}}, ""},
+		{"unknown rule", LintConfig{Severities: map[string]Severity{"operation-ids": SeverityError}}, `lint rule "operation-ids": unknown rule`},
+		{"custom rules", LintConfig{Rules: []LintRule{{Name: "custom"}}, Severities: map[string]Severity{"operation-id": SeverityOff}}, `lint rule "operation-id": unknown rule`},
+		{"invalid severity", LintConfig{Severities: map[string]Severity{"operation-id": "fatal"}}, `lint rule "operation-id": invalid severity "fatal": must be one of error, warning, info, off`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var configErr *LintConfigError
+			if !errors.As(err, &configErr) || err.Error() != tt.expectedError {
+				t.Fatalf("expected error %q, got %v", tt.expectedError, err)
+			}
+
+			if _, err := LintSpec([]byte(testSpec), tt.config); err == nil || err.Error() != tt.expectedError {
+				t.Errorf("expected LintSpec to return error %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,721 @@
+package go_swagger_ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ConflictStrategy controls how MergeSpecs resolves conflicts between specs.
+type ConflictStrategy int
+
+const (
+	// ConflictError aborts merging with an error that wraps ErrMergeConflict.
+	ConflictError ConflictStrategy = iota
+	// ConflictSkip keeps the element of the spec that has been merged first.
+	ConflictSkip
+	// ConflictOverwrite replaces the element with the element of the spec that is merged later.
+	ConflictOverwrite
+	// ConflictRename renames components and operation IDs of the spec that is merged later by prefixing
+	// them with the name of the source. Conflicting operations (same path and method) cannot be renamed
+	// and cause an error. Other conflicting fields of path items (e.g., the summary) are skipped.
+	ConflictRename
+)
+
+// ErrMergeConflict is returned by MergeSpecs if specs conflict and ConflictError is used.
+var ErrMergeConflict = errors.New("merge conflict")
+
+// MergeSource is a spec that is merged with MergeSpecs.
+type MergeSource struct {
+	// Name identifies the source in the report and is used to rename conflicting elements.
+	Name string
+	// Spec is the spec document (as YAML or JSON). Swagger 2.0 documents are converted to OpenAPI 3.0.
+	Spec []byte
+	// PathPrefix is prepended to all paths of the spec (e.g., "/billing").
+	PathPrefix string
+	// TagPrefix is prepended to all tags of the spec (e.g., "Billing: "), so that tags of different
+	// specs with the same name are not merged.
+	TagPrefix string
+}
+
+// MergeOptions configures MergeSpecs.
+type MergeOptions struct {
+	// Title, Version and Description set the info of the merged spec. The info of the first spec is used by default.
+	Title, Version, Description string
+	// Servers sets the servers of the merged spec (e.g., the URL of an API gateway). If not set, the servers
+	// of the specs are used for their paths, unless all specs have the same servers.
+	Servers []string
+	// Conflicts controls how conflicting operations, operation IDs, components and webhooks are resolved.
+	// Default is ConflictError. Operations and components that are equal are never conflicting.
+	Conflicts ConflictStrategy
+}
+
+// MergeAction is an action that has been taken by MergeSpecs.
+type MergeAction string
+
+const (
+	MergeAdded        MergeAction = "added"
+	MergeDeduplicated MergeAction = "deduplicated"
+	MergeSkipped      MergeAction = "skipped"
+	MergeOverwritten  MergeAction = "overwritten"
+	MergeRenamed      MergeAction = "renamed"
+)
+
+// MergeDecision describes how an element of a spec has been merged.
+type MergeDecision struct {
+	// Source is the name of the source the element belongs to.
+	Source string `json:"source"`
+	// Kind is the kind of the element ("path", "operation", "operationId", "component", "tag" or "webhook").
+	Kind string `json:"kind"`
+	// Name identifies the element (e.g., "GET /pets", "components/schemas/Pet" or "/pets summary" for a
+	// field of a path item).
+	Name string `json:"name"`
+	// Action is the action that has been taken.
+	Action MergeAction `json:"action"`
+	// NewName is the new name of renamed elements.
+	NewName string `json:"newName,omitempty"`
+}
+
+// String returns a human-readable representation of the decision.
+func (d MergeDecision) String() string {
+	if d.NewName != "" {
+		return fmt.Sprintf("%s: %s %s %s to %s", d.Source, d.Kind, d.Name, d.Action, d.NewName)
+	}
+	return fmt.Sprintf("%s: %s %s %s", d.Source, d.Kind, d.Name, d.Action)
+}
+
+// MergeReport contains all decisions made by MergeSpecs.
+type MergeReport struct {
+	Decisions []MergeDecision `json:"decisions"`
+}
+
+// Conflicts returns all decisions that resolved a conflict.
+func (r *MergeReport) Conflicts() []MergeDecision {
+	var conflicts []MergeDecision
+	for _, d := range r.Decisions {
+		if d.Action != MergeAdded && d.Action != MergeDeduplicated {
+			conflicts = append(conflicts, d)
+		}
+	}
+
+	return conflicts
+}
+
+// WithMergedSpecs sets the spec to the result of merging the given specs (see MergeSpecs).
+// NewHandler panics if the specs cannot be merged. Resolved conflicts are logged.
+func WithMergedSpecs(sources []MergeSource, options MergeOptions) Option {
+	return func(cfg *uiConfig) {
+		cfg.mergeSources = sources
+		cfg.mergeOptions = options
+	}
+}
+
+// mergeConfiguredSpecs merges the specs set with WithMergedSpecs.
+func mergeConfiguredSpecs(cfg *uiConfig) ([]byte, error) {
+	spec, report, err := MergeSpecs(cfg.mergeSources, cfg.mergeOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range report.Conflicts() {
+		slog.Info("merge conflict resolved", "decision", d.String())
+	}
+
+	return spec, nil
+}
+
+// MergeSpecs merges multiple specs into a single OpenAPI 3 JSON document. Paths are prefixed with the
+// path prefix of their source and tags with its tag prefix. Paths, operations, operation IDs, components,
+// tags and webhooks of all sources are merged in order and conflicts are resolved as configured.
+// The returned report describes every decision that has been made. OpenAPI 3.0 and 3.1 specs cannot be
+// merged with each other (Swagger 2.0 documents are converted to OpenAPI 3.0).
+func MergeSpecs(sources []MergeSource, options MergeOptions) ([]byte, *MergeReport, error) {
+	if len(sources) == 0 {
+		return nil, nil, errors.New("no specs to merge")
+	}
+
+	m := &merger{
+		options:      options,
+		report:       &MergeReport{},
+		result:       map[string]any{"openapi": "3.0.3", "paths": map[string]any{}},
+		operationIDs: make(map[string][2]string),
+	}
+
+	specs := make([]map[string]any, len(sources))
+	for idx, source := range sources {
+		data, err := yamlOrJSONToJSON(source.Spec)
+		if err == nil {
+			data, err = convertSpec(data)
+		}
+		if err == nil {
+			specs[idx], err = parseSpec(data)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot parse spec %q: %w", source.Name, err)
+		}
+	}
+
+	if err := m.mergeVersions(sources, specs); err != nil {
+		return nil, nil, err
+	}
+
+	m.mergeInfo(specs[0])
+
+	// Components are merged first, because renaming security schemes changes the security
+	// requirements, which must be compared afterwards.
+	for idx, source := range sources {
+		if err := m.mergeComponents(source, specs[idx]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	m.sharedServers, m.sharedSecurity = sharedValue(specs, "servers"), sharedValue(specs, "security")
+
+	for idx, source := range sources {
+		if err := m.merge(source, specs[idx]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	spec, err := marshalSpec(m.result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return spec, m.report, nil
+}
+
+type merger struct {
+	options MergeOptions
+	report  *MergeReport
+	result  map[string]any
+
+	// operationIDs maps operation IDs to the path and method of their operation.
+	operationIDs map[string][2]string
+
+	// sharedServers and sharedSecurity are set if all specs have the same servers or security requirements.
+	sharedServers, sharedSecurity bool
+}
+
+func (m *merger) decide(source MergeSource, kind, name string, action MergeAction, newName string) {
+	m.report.Decisions = append(m.report.Decisions, MergeDecision{
+		Source:  source.Name,
+		Kind:    kind,
+		Name:    name,
+		Action:  action,
+		NewName: newName,
+	})
+}
+
+func (m *merger) conflict(source MergeSource, kind, name string) error {
+	return fmt.Errorf("%w: %s %s of spec %q already exists", ErrMergeConflict, kind, name, source.Name)
+}
+
+// mergeVersions sets the version of the result to the highest OpenAPI version of all specs, which
+// must have the same major and minor version.
+func (m *merger) mergeVersions(sources []MergeSource, specs []map[string]any) error {
+	var minorVersion string
+	highestPatch := -1
+
+	for idx, spec := range specs {
+		version, _ := spec["openapi"].(string)
+		minor, patch, ok := parseOpenAPIVersion(version)
+		switch {
+		case !ok:
+			return fmt.Errorf("spec %q has an unsupported OpenAPI version %q", sources[idx].Name, version)
+		case minorVersion != "" && minor != minorVersion:
+			return fmt.Errorf("cannot merge OpenAPI %s spec %q with OpenAPI %s specs", minor, sources[idx].Name, minorVersion)
+		}
+
+		minorVersion = minor
+		if patch > highestPatch {
+			highestPatch = patch
+			m.result["openapi"] = version
+		}
+	}
+
+	return nil
+}
+
+// parseOpenAPIVersion returns the minor version (e.g., "3.1") and the patch version of an OpenAPI 3 version.
+func parseOpenAPIVersion(version string) (string, int, bool) {
+	var minor string
+	switch {
+	case openAPI30VersionPattern.MatchString(version):
+		minor = "3.0"
+	case openAPI31VersionPattern.MatchString(version):
+		minor = "3.1"
+	default:
+		return "", 0, false
+	}
+
+	patch, _, _ := strings.Cut(strings.TrimPrefix(version, minor+"."), "-")
+	patchVersion, err := strconv.Atoi(patch)
+
+	return minor, patchVersion, err == nil
+}
+
+func (m *merger) mergeInfo(first map[string]any) {
+	info, _ := first["info"].(map[string]any)
+	merged := make(map[string]any, len(info))
+	for key, value := range info {
+		merged[key] = value
+	}
+
+	for key, value := range map[string]string{
+		"title":       m.options.Title,
+		"version":     m.options.Version,
+		"description": m.options.Description,
+	} {
+		if value != "" {
+			merged[key] = value
+		}
+	}
+
+	m.result["info"] = merged
+}
+
+func (m *merger) merge(source MergeSource, spec map[string]any) error {
+	m.prefixTags(source, spec)
+	m.mergeTags(source, spec)
+
+	if err := m.mergePaths(source, spec); err != nil {
+		return err
+	}
+
+	if err := m.mergeNamed(source, spec, "webhooks", "webhook", m.result); err != nil {
+		return err
+	}
+
+	switch {
+	case len(m.options.Servers) > 0:
+		servers := make([]any, len(m.options.Servers))
+		for idx, server := range m.options.Servers {
+			servers[idx] = map[string]any{"url": server}
+		}
+		m.result["servers"] = servers
+	case m.sharedServers && spec["servers"] != nil:
+		m.result["servers"] = spec["servers"]
+	}
+
+	if m.sharedSecurity && spec["security"] != nil {
+		m.result["security"] = spec["security"]
+	}
+
+	return nil
+}
+
+// mergeComponents merges the components of a spec. Components are renamed before they are merged,
+// so that references to renamed components can be updated in the whole spec.
+func (m *merger) mergeComponents(source MergeSource, spec map[string]any) error {
+	components, _ := spec["components"].(map[string]any)
+	resultComponents, _ := m.result["components"].(map[string]any)
+	if resultComponents == nil {
+		resultComponents = make(map[string]any)
+	}
+
+	renamed := make(map[string]string)
+	for _, section := range sortedKeys(components) {
+		entries, _ := components[section].(map[string]any)
+		existing, _ := resultComponents[section].(map[string]any)
+
+		for _, name := range sortedKeys(entries) {
+			other, exists := existing[name]
+			if !exists || reflect.DeepEqual(other, entries[name]) || m.options.Conflicts != ConflictRename {
+				continue
+			}
+
+			newName := renamedName(source, name, func(candidate string) bool {
+				_, taken := existing[candidate]
+				_, takenInSpec := entries[candidate]
+				return taken || takenInSpec
+			})
+			renamed["#/components/"+section+"/"+escapeJSONPointer(name)] = "#/components/" + section + "/" + escapeJSONPointer(newName)
+			entries[newName] = entries[name]
+			delete(entries, name)
+			m.decide(source, "component", "components/"+section+"/"+name, MergeRenamed, newName)
+		}
+	}
+
+	if len(renamed) > 0 {
+		rewriteRefs(spec, func(ref string) string {
+			if newRef, ok := renamed[ref]; ok {
+				return newRef
+			}
+			return ref
+		})
+		renameSecuritySchemes(spec, renamed)
+	}
+
+	for _, section := range sortedKeys(components) {
+		if strings.HasPrefix(section, "x-") {
+			continue
+		}
+
+		existing, ok := resultComponents[section].(map[string]any)
+		if !ok {
+			existing = make(map[string]any)
+			resultComponents[section] = existing
+		}
+
+		if err := m.mergeNamed(source, components, section, "component", existing); err != nil {
+			return err
+		}
+	}
+
+	if len(resultComponents) > 0 {
+		m.result["components"] = resultComponents
+	}
+
+	return nil
+}
+
+// renameSecuritySchemes renames the security schemes in all security requirements of a spec, which
+// reference security schemes by name instead of $ref.
+func renameSecuritySchemes(spec map[string]any, renamedRefs map[string]string) {
+	const prefix = "#/components/securitySchemes/"
+
+	renamed := make(map[string]string)
+	for ref, newRef := range renamedRefs {
+		if strings.HasPrefix(ref, prefix) {
+			renamed[unescapeJSONPointer(strings.TrimPrefix(ref, prefix))] = unescapeJSONPointer(strings.TrimPrefix(newRef, prefix))
+		}
+	}
+	if len(renamed) == 0 {
+		return
+	}
+
+	rename := func(security any) {
+		requirements, _ := security.([]any)
+		for _, requirement := range requirements {
+			requirementObj, _ := requirement.(map[string]any)
+			for name, newName := range renamed {
+				if scopes, ok := requirementObj[name]; ok {
+					delete(requirementObj, name)
+					requirementObj[newName] = scopes
+				}
+			}
+		}
+	}
+
+	rename(spec["security"])
+
+	webhooks, _ := spec["webhooks"].(map[string]any)
+	for _, pathItems := range []map[string]any{lookupMap(spec, "paths"), webhooks} {
+		for _, pathItem := range pathItems {
+			for _, method := range httpMethods {
+				rename(lookup(pathItem, method, "security"))
+			}
+		}
+	}
+}
+
+// mergeNamed merges the named elements of spec[key] into target[key] or into target itself if
+// target is a components section.
+func (m *merger) mergeNamed(source MergeSource, spec map[string]any, key, kind string, target map[string]any) error {
+	entries, _ := spec[key].(map[string]any)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	existing := target
+	if kind == "webhook" {
+		existing, _ = target[key].(map[string]any)
+		if existing == nil {
+			existing = make(map[string]any)
+			target[key] = existing
+		}
+	}
+
+	for _, name := range sortedKeys(entries) {
+		displayName := name
+		if kind == "component" {
+			displayName = "components/" + key + "/" + name
+		}
+
+		other, exists := existing[name]
+		switch {
+		case !exists:
+			existing[name] = entries[name]
+			m.decide(source, kind, displayName, MergeAdded, "")
+		case reflect.DeepEqual(other, entries[name]):
+			m.decide(source, kind, displayName, MergeDeduplicated, "")
+		case m.options.Conflicts == ConflictSkip:
+			m.decide(source, kind, displayName, MergeSkipped, "")
+		case m.options.Conflicts == ConflictOverwrite:
+			existing[name] = entries[name]
+			m.decide(source, kind, displayName, MergeOverwritten, "")
+		default:
+			return m.conflict(source, kind, displayName)
+		}
+	}
+
+	return nil
+}
+
+func (m *merger) prefixTags(source MergeSource, spec map[string]any) {
+	if source.TagPrefix == "" {
+		return
+	}
+
+	tags, _ := spec["tags"].([]any)
+	for _, tag := range tags {
+		if tagObj, ok := tag.(map[string]any); ok {
+			tagObj["name"] = source.TagPrefix + fmt.Sprint(tagObj["name"])
+		}
+	}
+
+	forEachOperation(spec, func(op Operation, value map[string]any) bool {
+		if len(op.Tags) > 0 {
+			prefixed := make([]any, len(op.Tags))
+			for idx, tag := range op.Tags {
+				prefixed[idx] = source.TagPrefix + tag
+			}
+			value["tags"] = prefixed
+		}
+		return true
+	})
+}
+
+// mergeTags adds the tags of the spec that are not defined yet. Tags with the same name are
+// merged, so that operations of different specs can be grouped together.
+func (m *merger) mergeTags(source MergeSource, spec map[string]any) {
+	tags, _ := spec["tags"].([]any)
+	if len(tags) == 0 {
+		return
+	}
+
+	resultTags, _ := m.result["tags"].([]any)
+	defined := make(map[string]struct{}, len(resultTags))
+	for _, tag := range resultTags {
+		defined[fmt.Sprint(lookup(tag, "name"))] = struct{}{}
+	}
+
+	for _, tag := range tags {
+		name := fmt.Sprint(lookup(tag, "name"))
+		if _, ok := defined[name]; ok {
+			m.decide(source, "tag", name, MergeDeduplicated, "")
+			continue
+		}
+
+		defined[name] = struct{}{}
+		resultTags = append(resultTags, tag)
+		m.decide(source, "tag", name, MergeAdded, "")
+	}
+
+	m.result["tags"] = resultTags
+}
+
+func (m *merger) mergePaths(source MergeSource, spec map[string]any) error {
+	paths, _ := spec["paths"].(map[string]any)
+	resultPaths := m.result["paths"].(map[string]any)
+
+	for _, path := range sortedKeys(paths) {
+		pathItem, ok := paths[path].(map[string]any)
+		if !ok || strings.HasPrefix(path, "x-") {
+			continue
+		}
+
+		prefixedPath := path
+		if source.PathPrefix != "" {
+			prefixedPath = "/" + strings.Trim(source.PathPrefix, "/") + path
+		}
+
+		m.pushDown(spec, pathItem)
+
+		existing, exists := resultPaths[prefixedPath].(map[string]any)
+		if !exists {
+			existing = make(map[string]any)
+			resultPaths[prefixedPath] = existing
+			m.decide(source, "path", prefixedPath, MergeAdded, "")
+		}
+
+		// Parameters and servers of a path item apply to all of its operations, so they are moved to the
+		// operations if the path items differ.
+		for _, key := range []string{"parameters", "servers"} {
+			if exists && !reflect.DeepEqual(existing[key], pathItem[key]) {
+				m.moveToOperations(existing, key)
+				m.moveToOperations(pathItem, key)
+			}
+		}
+
+		for _, key := range sortedKeys(pathItem) {
+			value := pathItem[key]
+			if !slices.Contains(httpMethods, key) {
+				other, ok := existing[key]
+				switch {
+				case !ok:
+					existing[key] = value
+				case reflect.DeepEqual(other, value):
+				case m.options.Conflicts == ConflictOverwrite:
+					existing[key] = value
+					m.decide(source, "path", prefixedPath+" "+key, MergeOverwritten, "")
+				case m.options.Conflicts == ConflictError:
+					return m.conflict(source, "path", prefixedPath+" "+key)
+				default:
+					m.decide(source, "path", prefixedPath+" "+key, MergeSkipped, "")
+				}
+				continue
+			}
+
+			operation, _ := value.(map[string]any)
+			name := strings.ToUpper(key) + " " + prefixedPath
+
+			if other, ok := existing[key].(map[string]any); ok {
+				switch {
+				case reflect.DeepEqual(other, operation):
+					m.decide(source, "operation", name, MergeDeduplicated, "")
+					continue
+				case m.options.Conflicts == ConflictSkip:
+					m.decide(source, "operation", name, MergeSkipped, "")
+					continue
+				case m.options.Conflicts == ConflictOverwrite:
+					if operationID, ok := other["operationId"].(string); ok {
+						delete(m.operationIDs, operationID)
+					}
+					m.decide(source, "operation", name, MergeOverwritten, "")
+				default:
+					return m.conflict(source, "operation", name)
+				}
+			}
+
+			if err := m.mergeOperationID(source, operation, prefixedPath, key); err != nil {
+				return err
+			}
+			existing[key] = operation
+		}
+	}
+
+	return nil
+}
+
+// moveToOperations moves the parameters or servers of a path item to its operations. Parameters of
+// operations override the parameters of the path item with the same name and location.
+func (m *merger) moveToOperations(pathItem map[string]any, key string) {
+	value, ok := pathItem[key]
+	if !ok {
+		return
+	}
+	delete(pathItem, key)
+
+	for _, method := range httpMethods {
+		operation, ok := pathItem[method].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if key == "servers" {
+			if _, ok := operation["servers"]; !ok {
+				operation["servers"] = value
+			}
+			continue
+		}
+
+		params, _ := operation["parameters"].([]any)
+		defined := make(map[string]struct{}, len(params))
+		for _, param := range params {
+			defined[m.parameterKey(param)] = struct{}{}
+		}
+
+		pathParams, _ := value.([]any)
+		for _, param := range pathParams {
+			if _, overridden := defined[m.parameterKey(param)]; !overridden {
+				params = append(params, param)
+			}
+		}
+		operation["parameters"] = params
+	}
+}
+
+// parameterKey returns the location and name of a parameter, which identify it within an operation.
+func (m *merger) parameterKey(param any) string {
+	if ref, ok := lookup(param, "$ref").(string); ok {
+		// Components have already been merged into the result.
+		if target, ok := resolveRef(m.result, ref); ok {
+			param = target
+		}
+	}
+
+	return fmt.Sprint(lookup(param, "in")) + "/" + fmt.Sprint(lookup(param, "name"))
+}
+
+// pushDown moves the servers and security requirements of a spec to the operations of a path item,
+// if they are not the same for all specs.
+func (m *merger) pushDown(spec map[string]any, pathItem map[string]any) {
+	if servers, ok := spec["servers"]; ok && !m.sharedServers && len(m.options.Servers) == 0 {
+		if _, ok := pathItem["servers"]; !ok {
+			pathItem["servers"] = servers
+		}
+	}
+
+	if security, ok := spec["security"]; ok && !m.sharedSecurity {
+		for _, method := range httpMethods {
+			if operation, ok := pathItem[method].(map[string]any); ok && operation["security"] == nil {
+				operation["security"] = security
+			}
+		}
+	}
+}
+
+func (m *merger) mergeOperationID(source MergeSource, operation map[string]any, path, method string) error {
+	operationID, ok := operation["operationId"].(string)
+	if !ok {
+		return nil
+	}
+
+	other, exists := m.operationIDs[operationID]
+	if !exists {
+		m.operationIDs[operationID] = [2]string{path, method}
+		return nil
+	}
+
+	switch m.options.Conflicts {
+	case ConflictSkip:
+		delete(operation, "operationId")
+		m.decide(source, "operationId", operationID, MergeSkipped, "")
+	case ConflictOverwrite:
+		if otherOperation, ok := lookup(m.result, "paths", other[0], other[1]).(map[string]any); ok {
+			delete(otherOperation, "operationId")
+		}
+		m.operationIDs[operationID] = [2]string{path, method}
+		m.decide(source, "operationId", operationID, MergeOverwritten, "")
+	case ConflictRename:
+		newID := renamedName(source, operationID, func(candidate string) bool {
+			_, taken := m.operationIDs[candidate]
+			return taken
+		})
+		operation["operationId"] = newID
+		m.operationIDs[newID] = [2]string{path, method}
+		m.decide(source, "operationId", operationID, MergeRenamed, newID)
+	default:
+		return m.conflict(source, "operationId", operationID)
+	}
+
+	return nil
+}
+
+// renamedName returns the name prefixed with the name of the source that is not taken yet.
+func renamedName(source MergeSource, name string, taken func(string) bool) string {
+	prefix := invalidComponentNameChars.ReplaceAllString(source.Name, "_")
+	candidate := prefix + "_" + name
+	for idx := 2; taken(candidate); idx++ {
+		candidate = fmt.Sprintf("%s_%s%d", prefix, name, idx)
+	}
+
+	return candidate
+}
+
+// sharedValue reports whether all specs have the same value for the key.
+func sharedValue(specs []map[string]any, key string) bool {
+	for _, spec := range specs[1:] {
+		if !reflect.DeepEqual(spec[key], specs[0][key]) {
+			return false
+		}
+	}
+
+	return true
+}
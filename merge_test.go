@@ -0,0 +1,263 @@
+package go_swagger_ui
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var (
+	mergeTestSpecA = MergeSource{Name: "a", Spec: []byte(`
+openapi: 3.0.3
+info: {title: A, version: "1"}
+servers: [{url: "https://a.example.com"}]
+security: [{auth: []}]
+paths:
+  /pets:
+    summary: Pets
+    parameters: [{name: X-Tenant, in: header}]
+    get:
+      operationId: listPets
+      parameters: [{$ref: "#/components/parameters/Tenant"}]
+      responses: {"200": {description: ok, content: {application/json: {schema: {$ref: "#/components/schemas/Pet"}}}}}
+components:
+  parameters:
+    Tenant: {name: X-Tenant, in: header, required: true}
+  schemas:
+    Pet: {type: object, properties: {name: {type: string}}}
+    Error: {type: object}
+  securitySchemes:
+    auth: {type: http, scheme: basic}
+`)}
+
+	mergeTestSpecB = MergeSource{Name: "b", Spec: []byte(`
+openapi: 3.0.10
+info: {title: B, version: "2"}
+servers: [{url: "https://b.example.com"}]
+security: [{auth: []}]
+paths:
+  /pets:
+    summary: Other pets
+    parameters: [{name: X-Region, in: header}]
+    post:
+      operationId: createPet
+      parameters: [{name: X-Region, in: header, required: true}]
+      requestBody: {content: {application/json: {schema: {$ref: "#/components/schemas/Pet"}}}}
+      responses: {"201": {description: created}}
+  /owners:
+    get:
+      operationId: listPets
+      security: [{auth: []}, {}]
+      responses: {"200": {description: ok}}
+components:
+  schemas:
+    Pet: {type: object, properties: {id: {type: integer}}}
+    Error: {type: object}
+  securitySchemes:
+    auth: {type: http, scheme: bearer}
+`)}
+)
+
+func TestMergeSpecs(t *testing.T) {
+	// The parameters and servers of both path items are moved to the operations of /pets.
+	pathItemChecks := map[string]string{
+		"/openapi":                           `"3.0.10"`,
+		"/info/title":                        `"A"`,
+		"/paths/~1pets/parameters":           `null`,
+		"/paths/~1pets/servers":              `null`,
+		"/paths/~1pets/get/parameters":       `[{"$ref":"#/components/parameters/Tenant"}]`,
+		"/paths/~1pets/get/servers":          `[{"url":"https://a.example.com"}]`,
+		"/paths/~1pets/post/parameters":      `[{"in":"header","name":"X-Region","required":true}]`,
+		"/paths/~1pets/post/servers":         `[{"url":"https://b.example.com"}]`,
+		"/components/schemas/Error":          `{"type":"object"}`,
+		"/paths/~1owners/servers":            `[{"url":"https://b.example.com"}]`,
+		"/components/parameters/Tenant/name": `"X-Tenant"`,
+	}
+
+	tests := []struct {
+		name              string
+		conflicts         ConflictStrategy
+		expectedConflicts []MergeDecision
+		expected          map[string]string
+	}{
+		{
+			name:      "skip",
+			conflicts: ConflictSkip,
+			expectedConflicts: []MergeDecision{
+				{Source: "b", Kind: "component", Name: "components/schemas/Pet", Action: MergeSkipped},
+				{Source: "b", Kind: "component", Name: "components/securitySchemes/auth", Action: MergeSkipped},
+				{Source: "b", Kind: "operationId", Name: "listPets", Action: MergeSkipped},
+				{Source: "b", Kind: "path", Name: "/pets summary", Action: MergeSkipped},
+			},
+			expected: map[string]string{
+				"/paths/~1pets/summary":              `"Pets"`,
+				"/paths/~1pets/get/operationId":      `"listPets"`,
+				"/paths/~1owners/get/operationId":    `null`,
+				"/components/schemas/Pet/properties": `{"name":{"type":"string"}}`,
+				"/components/securitySchemes/auth":   `{"scheme":"basic","type":"http"}`,
+				"/security":                          `[{"auth":[]}]`,
+				"/paths/~1pets/post/security":        `null`,
+				"/paths/~1pets/post/requestBody/content/application~1json/schema/$ref": `"#/components/schemas/Pet"`,
+			},
+		},
+		{
+			name:      "overwrite",
+			conflicts: ConflictOverwrite,
+			expectedConflicts: []MergeDecision{
+				{Source: "b", Kind: "component", Name: "components/schemas/Pet", Action: MergeOverwritten},
+				{Source: "b", Kind: "component", Name: "components/securitySchemes/auth", Action: MergeOverwritten},
+				{Source: "b", Kind: "operationId", Name: "listPets", Action: MergeOverwritten},
+				{Source: "b", Kind: "path", Name: "/pets summary", Action: MergeOverwritten},
+			},
+			expected: map[string]string{
+				"/paths/~1pets/summary":              `"Other pets"`,
+				"/paths/~1pets/get/operationId":      `null`,
+				"/paths/~1owners/get/operationId":    `"listPets"`,
+				"/components/schemas/Pet/properties": `{"id":{"type":"integer"}}`,
+				"/components/securitySchemes/auth":   `{"scheme":"bearer","type":"http"}`,
+				"/security":                          `[{"auth":[]}]`,
+			},
+		},
+		{
+			name:      "rename",
+			conflicts: ConflictRename,
+			expectedConflicts: []MergeDecision{
+				{Source: "b", Kind: "component", Name: "components/schemas/Pet", Action: MergeRenamed, NewName: "b_Pet"},
+				{Source: "b", Kind: "component", Name: "components/securitySchemes/auth", Action: MergeRenamed, NewName: "b_auth"},
+				{Source: "b", Kind: "operationId", Name: "listPets", Action: MergeRenamed, NewName: "b_listPets"},
+				{Source: "b", Kind: "path", Name: "/pets summary", Action: MergeSkipped},
+			},
+			expected: map[string]string{
+				"/paths/~1pets/summary":                                                `"Pets"`,
+				"/paths/~1pets/get/operationId":                                        `"listPets"`,
+				"/paths/~1owners/get/operationId":                                      `"b_listPets"`,
+				"/components/schemas/Pet/properties":                                   `{"name":{"type":"string"}}`,
+				"/components/schemas/b_Pet/properties":                                 `{"id":{"type":"integer"}}`,
+				"/components/securitySchemes/auth":                                     `{"scheme":"basic","type":"http"}`,
+				"/components/securitySchemes/b_auth":                                   `{"scheme":"bearer","type":"http"}`,
+				"/paths/~1pets/post/requestBody/content/application~1json/schema/$ref": `"#/components/schemas/b_Pet"`,
+				// The security requirements differ after renaming, so they are moved to the operations.
+				"/security":                    `null`,
+				"/paths/~1pets/get/security":   `[{"auth":[]}]`,
+				"/paths/~1pets/post/security":  `[{"b_auth":[]}]`,
+				"/paths/~1owners/get/security": `[{"b_auth":[]},{}]`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, report, err := MergeSpecs([]MergeSource{mergeTestSpecA, mergeTestSpecB}, MergeOptions{Conflicts: tt.conflicts})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if conflicts := report.Conflicts(); !reflect.DeepEqual(conflicts, tt.expectedConflicts) {
+				t.Errorf("expected conflicts\n%v\ngot\n%v", tt.expectedConflicts, conflicts)
+			}
+
+			spec, err := parseSpec(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, expected := range []map[string]string{pathItemChecks, tt.expected} {
+				for pointer, expectedValue := range expected {
+					value, _ := resolveRef(spec, "#"+pointer)
+					if actual, _ := json.Marshal(value); string(actual) != expectedValue {
+						t.Errorf("expected %s to be %s, got %s", pointer, expectedValue, actual)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMergeSpecsConflictError(t *testing.T) {
+	_, _, err := MergeSpecs([]MergeSource{mergeTestSpecA, mergeTestSpecB}, MergeOptions{Conflicts: ConflictError})
+	if !errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("expected a merge conflict, got %v", err)
+	}
+
+	if expected := `merge conflict: component components/schemas/Pet of spec "b" already exists`; err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err)
+	}
+
+	// Equal elements are never conflicting.
+	data, report, err := MergeSpecs([]MergeSource{mergeTestSpecA, {Name: "c", Spec: mergeTestSpecA.Spec}}, MergeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conflicts := report.Conflicts(); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+	if spec, _ := parseSpec(data); lookup(spec, "paths", "/pets", "parameters") == nil {
+		t.Error("expected the parameters of equal path items to be kept")
+	}
+}
+
+func TestMergeSpecsConflictingOperations(t *testing.T) {
+	other := MergeSource{Name: "c", Spec: []byte(`
+openapi: 3.0.3
+info: {title: C, version: "1"}
+paths:
+  /pets:
+    get: {operationId: getPets, responses: {}}
+`)}
+
+	for _, conflicts := range []ConflictStrategy{ConflictError, ConflictRename} {
+		if _, _, err := MergeSpecs([]MergeSource{mergeTestSpecA, other}, MergeOptions{Conflicts: conflicts}); !errors.Is(err, ErrMergeConflict) {
+			t.Errorf("expected a merge conflict for strategy %d, got %v", conflicts, err)
+		}
+	}
+
+	_, report, err := MergeSpecs([]MergeSource{mergeTestSpecA, other}, MergeOptions{Conflicts: ConflictSkip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []MergeDecision{{Source: "c", Kind: "operation", Name: "GET /pets", Action: MergeSkipped}}
+	if conflicts := report.Conflicts(); !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("expected conflicts %v, got %v", expected, conflicts)
+	}
+}
+
+func TestMergeSpecsVersions(t *testing.T) {
+	spec := func(version string) MergeSource {
+		return MergeSource{Name: version, Spec: []byte(`{"openapi": "` + version + `", "info": {"title": "Pets", "version": "1"}, "paths": {}}`)}
+	}
+	swagger2 := MergeSource{Name: "swagger", Spec: []byte(`{"swagger": "2.0", "info": {"title": "Pets", "version": "1"}, "paths": {}}`)}
+
+	tests := []struct {
+		name            string
+		sources         []MergeSource
+		expectedVersion string
+		expectedError   string
+	}{
+		{"highest patch version", []MergeSource{spec("3.0.9"), spec("3.0.10"), spec("3.0.2")}, "3.0.10", ""},
+		{"OpenAPI 3.1", []MergeSource{spec("3.1.1"), spec("3.1.0")}, "3.1.1", ""},
+		{"Swagger 2.0", []MergeSource{swagger2, spec("3.0.0")}, "3.0.3", ""},
+		{"OpenAPI 3.0 and 3.1", []MergeSource{spec("3.0.3"), spec("3.1.0")}, "", `cannot merge OpenAPI 3.1 spec "3.1.0" with OpenAPI 3.0 specs`},
+		{"Swagger 2.0 and OpenAPI 3.1", []MergeSource{spec("3.1.0"), swagger2}, "", `cannot merge OpenAPI 3.0 spec "swagger" with OpenAPI 3.1 specs`},
+		{"unsupported version", []MergeSource{spec("3.0.3"), spec("4.0")}, "", `spec "4.0" has an unsupported OpenAPI version "4.0"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _, err := MergeSpecs(tt.sources, MergeOptions{})
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Fatalf("expected error %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if merged, _ := parseSpec(data); merged["openapi"] != tt.expectedVersion {
+				t.Errorf("expected version %s, got %v", tt.expectedVersion, merged["openapi"])
+			}
+		})
+	}
+}
@@ -0,0 +1,555 @@
+package go_swagger_ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// mockEndpoint is the path segment the mock server is served on when enabled with WithMockServer.
+const mockEndpoint = "_mock"
+
+// MockServer configures a mock server that answers requests for all operations of a spec using
+// the examples of the spec or sample data generated from schemas. See WithMockServer and NewMockHandler.
+type MockServer struct {
+	// StatusHeader is the name of the request header that can be used to select the status code
+	// of the response (e.g., "X-Mock-Status: 404"). By default, the first successful (2xx) response
+	// of an operation is returned. Default is "X-Mock-Status".
+	StatusHeader string
+
+	// ExampleHeader is the name of the request header that can be used to select a named example
+	// of the response (OpenAPI 3 only). By default, the first example is returned.
+	// Default is "X-Mock-Example".
+	ExampleHeader string
+
+	// DisableValidation disables validating request parameters and bodies against the spec.
+	// By default, requests that do not match the spec are answered with status code 400 (Bad Request).
+	DisableValidation bool
+}
+
+// WithMockServer enables a mock server that answers "Try it out" requests for all operations in the spec
+// using the examples of the spec or sample data generated from schemas, so that Swagger UI can be used
+// before the API has been implemented. The mock server is served on path "_mock" below the path of the
+// handler (e.g., "/swagger-ui/_mock/pets" for path "/pets"). The servers of the spec are replaced
+// with the mock server URL. This option only has an effect on specs that are embedded into Swagger UI
+// (see WithSpec and WithSpecFilePath). The mock server URL is relative to the index.html page, unless
+// a base path has been set (see WithBasePath). For Swagger 2 specs, which do not support relative
+// server URLs, the handler is expected to be served on path "/" if no base path has been set.
+func WithMockServer(mock MockServer) Option {
+	return func(cfg *uiConfig) {
+		cfg.mockServer = configValue[MockServer]{Value: withMockDefaults(mock), IsSet: true}
+	}
+}
+
+// NewMockHandler creates a new http.Handler that serves a mock server for the given spec
+// (OpenAPI 3 or Swagger 2, as YAML or JSON). Paths of the spec are served as they are,
+// which means that paths of servers defined in the spec are ignored.
+// See WithMockServer to serve a mock server along with Swagger UI.
+func NewMockHandler(spec []byte, mock MockServer) (http.Handler, error) {
+	jsonSpec, err := yamlOrJSONToJSON(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMockHandler(func() []byte { return jsonSpec }, withMockDefaults(mock)), nil
+}
+
+func withMockDefaults(mock MockServer) MockServer {
+	if mock.StatusHeader == "" {
+		mock.StatusHeader = "X-Mock-Status"
+	}
+
+	if mock.ExampleHeader == "" {
+		mock.ExampleHeader = "X-Mock-Example"
+	}
+
+	return mock
+}
+
+// mockServerURL returns the URL of the mock server that is set as the server of the spec.
+func mockServerURL(cfg *uiConfig) string {
+	if cfg.basePath != "" {
+		return cfg.basePath + mockEndpoint
+	}
+
+	return "./" + mockEndpoint
+}
+
+// useMockServer replaces the servers of the JSON spec document with the mock server URL.
+func useMockServer(data []byte, cfg *uiConfig) ([]byte, error) {
+	spec, err := parseSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if isSwagger2(spec) {
+		delete(spec, "host")
+		delete(spec, "schemes")
+		spec["basePath"] = "/" + strings.TrimPrefix(strings.TrimPrefix(mockServerURL(cfg), "."), "/")
+	} else {
+		setServers(spec, []string{mockServerURL(cfg)})
+	}
+
+	return marshalSpec(spec)
+}
+
+type mockRoute struct {
+	path       string
+	method     string
+	pattern    *regexp.Regexp
+	paramNames []string
+	pathItem   map[string]any
+	operation  map[string]any
+}
+
+type mockHandler struct {
+	settings MockServer
+	spec     func() []byte
+
+	mu         sync.Mutex
+	routesSpec []byte
+	parsed     map[string]any
+	routes     []mockRoute
+}
+
+func newMockHandler(spec func() []byte, settings MockServer) *mockHandler {
+	return &mockHandler{settings: settings, spec: spec}
+}
+
+// load returns the parsed spec along with all routes. Routes are recalculated whenever
+// the spec changes (see WithSpecFilePath).
+func (m *mockHandler) load() (map[string]any, []mockRoute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data := m.spec()
+	if m.parsed != nil && sameBytes(m.routesSpec, data) {
+		return m.parsed, m.routes, nil
+	}
+
+	spec, err := parseSpec(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var routes []mockRoute
+	forEachOperation(spec, func(op Operation, operation map[string]any) bool {
+		pathItem, _ := lookup(spec, "paths", op.Path).(map[string]any)
+		pattern, paramNames := compilePathTemplate(op.Path)
+
+		routes = append(routes, mockRoute{
+			path:       op.Path,
+			method:     op.Method,
+			pattern:    pattern,
+			paramNames: paramNames,
+			pathItem:   pathItem,
+			operation:  operation,
+		})
+
+		return true
+	})
+
+	// Paths without parameters take precedence over templated paths (e.g., "/pets/mine" over "/pets/{id}").
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].paramNames) < len(routes[j].paramNames)
+	})
+
+	m.routesSpec, m.parsed, m.routes = data, spec, routes
+
+	return spec, routes, nil
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}/]+)}`)
+
+// compilePathTemplate compiles a path template (e.g., "/pets/{id}") into a regular expression
+// and returns the names of all path parameters.
+func compilePathTemplate(path string) (*regexp.Regexp, []string) {
+	var names []string
+	var expr strings.Builder
+
+	expr.WriteString("^")
+	last := 0
+	for _, match := range pathParamPattern.FindAllStringSubmatchIndex(path, -1) {
+		expr.WriteString(regexp.QuoteMeta(path[last:match[0]]))
+		expr.WriteString("([^/]+)")
+		names = append(names, path[match[2]:match[3]])
+		last = match[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(path[last:]))
+	expr.WriteString("$")
+
+	return regexp.MustCompile(expr.String()), names
+}
+
+func (m *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	spec, routes, err := m.load()
+	if err != nil {
+		slog.Error("cannot load spec for mock server", "err", err.Error())
+		sendMockError(w, http.StatusInternalServerError, "cannot load spec")
+		return
+	}
+
+	route, pathParams, status := matchRoute(routes, r)
+	if route == nil {
+		sendMockError(w, status, fmt.Sprintf("no operation found for %s %s", r.Method, r.URL.Path))
+		return
+	}
+
+	if !m.settings.DisableValidation {
+		problems, status := validateRequest(spec, route, pathParams, r)
+		if len(problems) > 0 {
+			sendMockError(w, status, "request validation failed", problems...)
+			return
+		}
+	}
+
+	m.respond(w, r, spec, route)
+}
+
+// matchRoute finds the route for a request. If no route is found, the returned status code
+// is either 404 (Not Found) or 405 (Method Not Allowed).
+func matchRoute(routes []mockRoute, r *http.Request) (*mockRoute, map[string]string, int) {
+	status := http.StatusNotFound
+	method := strings.ToLower(r.Method)
+
+	for idx := range routes {
+		route := &routes[idx]
+
+		matches := route.pattern.FindStringSubmatch(r.URL.Path)
+		if matches == nil {
+			continue
+		}
+
+		if route.method != method {
+			status = http.StatusMethodNotAllowed
+			continue
+		}
+
+		pathParams := make(map[string]string, len(route.paramNames))
+		for i, name := range route.paramNames {
+			pathParams[name] = matches[i+1]
+		}
+
+		return route, pathParams, 0
+	}
+
+	return nil, nil, status
+}
+
+// operationParameters returns all parameters of an operation, including the parameters
+// defined on the path item that are not overridden by the operation.
+func operationParameters(spec map[string]any, pathItem, operation map[string]any) []map[string]any {
+	var params []map[string]any
+	seen := make(map[string]struct{})
+
+	for _, source := range []map[string]any{operation, pathItem} {
+		list, _ := source["parameters"].([]any)
+		for _, param := range list {
+			p := deref(spec, param)
+			if p == nil {
+				continue
+			}
+
+			name, _ := p["name"].(string)
+			in, _ := p["in"].(string)
+			if _, ok := seen[in+":"+name]; ok {
+				continue
+			}
+
+			seen[in+":"+name] = struct{}{}
+			params = append(params, p)
+		}
+	}
+
+	return params
+}
+
+// validateRequest validates request parameters and the request body and returns a list
+// of problems along with the status code that should be sent.
+func validateRequest(spec map[string]any, route *mockRoute, pathParams map[string]string, r *http.Request) ([]string, int) {
+	var problems []string
+
+	var bodySchema any
+	var bodyRequired bool
+
+	for _, param := range operationParameters(spec, route.pathItem, route.operation) {
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		required, _ := param["required"].(bool)
+
+		// Swagger 2 parameters have no schema, but the parameter object contains
+		// the same fields as a schema.
+		schema, ok := param["schema"]
+		if !ok {
+			schema = param
+		}
+
+		var raw []string
+		switch in {
+		case "path":
+			raw = []string{pathParams[name]}
+		case "query":
+			raw = r.URL.Query()[name]
+		case "header":
+			raw = r.Header.Values(name)
+		case "cookie":
+			if cookie, err := r.Cookie(name); err == nil {
+				raw = []string{cookie.Value}
+			}
+		case "body":
+			bodySchema, bodyRequired = schema, required
+			continue
+		default:
+			continue
+		}
+
+		if len(raw) == 0 {
+			if required {
+				problems = append(problems, fmt.Sprintf("missing required %s parameter %q", in, name))
+			}
+			continue
+		}
+
+		value := parseParameterValue(spec, schema, raw)
+		problems = append(problems, validateValue(spec, schema, value, fmt.Sprintf("%s parameter %q", in, name))...)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		return []string{"cannot read request body"}, http.StatusBadRequest
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	if requestBody := deref(spec, route.operation["requestBody"]); requestBody != nil {
+		bodyRequired, _ = requestBody["required"].(bool)
+
+		content, _ := requestBody["content"].(map[string]any)
+		if len(body) > 0 && len(content) > 0 {
+			mediaType, ok := content[contentType].(map[string]any)
+			if !ok {
+				mediaType, ok = matchMediaType(content, contentType)
+			}
+
+			if !ok {
+				return []string{fmt.Sprintf("unsupported content type %q", contentType)}, http.StatusUnsupportedMediaType
+			}
+
+			bodySchema = mediaType["schema"]
+		}
+	}
+
+	if len(body) == 0 {
+		if bodyRequired {
+			problems = append(problems, "missing required request body")
+		}
+		return problems, http.StatusBadRequest
+	}
+
+	if bodySchema != nil && strings.Contains(contentType, "json") {
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			return append(problems, "request body is not valid JSON"), http.StatusBadRequest
+		}
+
+		problems = append(problems, validateValue(spec, bodySchema, value, "body")...)
+	}
+
+	return problems, http.StatusBadRequest
+}
+
+// matchMediaType finds the media type object for a content type using wildcard
+// media ranges (e.g., "application/*" or "*/*") of the content map.
+func matchMediaType(content map[string]any, contentType string) (map[string]any, bool) {
+	mainType, _, _ := strings.Cut(contentType, "/")
+
+	for _, key := range []string{mainType + "/*", "*/*"} {
+		if mediaType, ok := content[key].(map[string]any); ok {
+			return mediaType, true
+		}
+	}
+
+	return nil, false
+}
+
+func (m *mockHandler) respond(w http.ResponseWriter, r *http.Request, spec map[string]any, route *mockRoute) {
+	responses, _ := route.operation["responses"].(map[string]any)
+
+	status, responseKey, err := selectResponse(responses, r.Header.Get(m.settings.StatusHeader))
+	if err != nil {
+		sendMockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := deref(spec, responses[responseKey])
+
+	var contentType string
+	var body any
+	var hasBody bool
+
+	if isSwagger2(spec) {
+		contentType, body, hasBody = swagger2ResponseBody(spec, route.operation, response, r.Header.Get("Accept"))
+	} else {
+		contentType, body, hasBody = openAPI3ResponseBody(spec, response, r.Header.Get("Accept"),
+			r.Header.Get(m.settings.ExampleHeader))
+	}
+
+	if !hasBody {
+		w.WriteHeader(status)
+		return
+	}
+
+	var data []byte
+	if s, ok := body.(string); ok && !strings.Contains(contentType, "json") {
+		data = []byte(s)
+	} else if data, err = json.MarshalIndent(body, "", "  "); err != nil {
+		sendMockError(w, http.StatusInternalServerError, "cannot encode response body")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// selectResponse selects the response of an operation. If requested is empty, the first successful
+// response is selected. It returns the status code to send and the key of the response in responses.
+func selectResponse(responses map[string]any, requested string) (int, string, error) {
+	keys := sortedKeys(responses)
+
+	if requested != "" {
+		status, err := strconv.Atoi(requested)
+		if err != nil || status < 100 || status > 599 {
+			return 0, "", fmt.Errorf("invalid mock status %q", requested)
+		}
+
+		for _, key := range []string{requested, requested[:1] + "XX", "default"} {
+			if _, ok := responses[key]; ok {
+				return status, key, nil
+			}
+		}
+
+		return 0, "", fmt.Errorf("operation does not define a response for status %s", requested)
+	}
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, "2") {
+			status, err := strconv.Atoi(key)
+			if err != nil {
+				status = http.StatusOK // e.g., "2XX"
+			}
+			return status, key, nil
+		}
+	}
+
+	if _, ok := responses["default"]; ok {
+		return http.StatusOK, "default", nil
+	}
+
+	for _, key := range keys {
+		if status, err := strconv.Atoi(key); err == nil {
+			return status, key, nil
+		}
+	}
+
+	return http.StatusNoContent, "", nil
+}
+
+func openAPI3ResponseBody(spec map[string]any, response map[string]any, accept, exampleName string) (string, any, bool) {
+	content, _ := response["content"].(map[string]any)
+	if len(content) == 0 {
+		return "", nil, false
+	}
+
+	contentType := negotiateContentType(sortedKeys(content), accept)
+	mediaType, _ := content[contentType].(map[string]any)
+
+	if example, ok := mediaType["example"]; ok {
+		return contentType, example, true
+	}
+
+	if examples, ok := mediaType["examples"].(map[string]any); ok && len(examples) > 0 {
+		name := sortedKeys(examples)[0]
+		if _, ok := examples[exampleName]; ok {
+			name = exampleName
+		}
+
+		if value, ok := deref(spec, examples[name])["value"]; ok {
+			return contentType, value, true
+		}
+	}
+
+	return contentType, schemaSample(spec, mediaType["schema"]), true
+}
+
+func swagger2ResponseBody(spec map[string]any, operation, response map[string]any, accept string) (string, any, bool) {
+	schema, hasSchema := response["schema"]
+	examples, _ := response["examples"].(map[string]any)
+	if !hasSchema && len(examples) == 0 {
+		return "", nil, false
+	}
+
+	produces, _ := operation["produces"].([]any)
+	if len(produces) == 0 {
+		produces, _ = spec["produces"].([]any)
+	}
+
+	var contentTypes []string
+	for _, contentType := range produces {
+		contentTypes = append(contentTypes, fmt.Sprint(contentType))
+	}
+	if len(contentTypes) == 0 {
+		contentTypes = []string{"application/json"}
+	}
+
+	contentType := negotiateContentType(contentTypes, accept)
+	if example, ok := examples[contentType]; ok {
+		return contentType, example, true
+	}
+
+	return contentType, schemaSample(spec, schema), true
+}
+
+// negotiateContentType selects the content type for a response based on the Accept header.
+// JSON is preferred if the client accepts any content type.
+func negotiateContentType(available []string, accept string) string {
+	for _, accepted := range strings.Split(accept, ",") {
+		mediaRange, _, _ := strings.Cut(strings.TrimSpace(accepted), ";")
+		mainType, _, _ := strings.Cut(mediaRange, "/")
+
+		for _, contentType := range available {
+			if mediaRange == contentType || mediaRange == mainType+"/*" && strings.HasPrefix(contentType, mainType+"/") {
+				return contentType
+			}
+		}
+	}
+
+	for _, contentType := range available {
+		if strings.Contains(contentType, "json") {
+			return contentType
+		}
+	}
+
+	return available[0]
+}
+
+func sendMockError(w http.ResponseWriter, status int, message string, problems ...string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(struct {
+		Message string   `json:"message"`
+		Errors  []string `json:"errors,omitempty"`
+	}{
+		Message: message,
+		Errors:  problems,
+	})
+}
@@ -0,0 +1,54 @@
+package go_swagger_ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const mockTestSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+        default:
+          description: error
+`
+
+func TestMockServerStatusHeader(t *testing.T) {
+	handler := NewHandler(WithSpec([]byte(mockTestSpec)), WithMockServer(MockServer{}))
+
+	tests := []struct {
+		status   string
+		expected int
+	}{
+		{status: "", expected: http.StatusOK},
+		{status: "503", expected: http.StatusServiceUnavailable},
+		{status: "99", expected: http.StatusBadRequest},
+		{status: "42", expected: http.StatusBadRequest},
+		{status: "600", expected: http.StatusBadRequest},
+		{status: "5000", expected: http.StatusBadRequest},
+		{status: "abc", expected: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/_mock/pets", nil)
+			if tt.status != "" {
+				req.Header.Set("X-Mock-Status", tt.status)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, rec.Code)
+			}
+		})
+	}
+}
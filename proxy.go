@@ -0,0 +1,485 @@
+package go_swagger_ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxyEndpoint is the file name the "Try it out" proxy is served on.
+const proxyEndpoint = "_proxy"
+
+// TryItOutProxy configures the same-origin proxy that "Try it out" requests are sent through.
+// See WithTryItOutProxy.
+type TryItOutProxy struct {
+	// AllowedHosts is a list of additional hosts (e.g., "api.example.com" or "api.example.com:8443")
+	// requests may be forwarded to. The hosts of all servers and OAuth2 token URLs in the spec
+	// are allowed automatically.
+	AllowedHosts []string
+
+	// AllowedHostsFunc is called for every proxied request and returns additional hosts requests may be
+	// forwarded to. It can be used to allow hosts that change while the handler is running (e.g., the
+	// hosts of specs configured with WithSpecURLs, see SpecHosts).
+	AllowedHostsFunc func() []string
+
+	// Timeout is the maximum duration of a proxied request, including reading the response body.
+	// Default is 30 seconds.
+	Timeout time.Duration
+
+	// MaxRequestBodySize is the maximum size of a request body in bytes. Default is 10 MiB.
+	MaxRequestBodySize int64
+
+	// MaxResponseBodySize is the maximum size of a response body in bytes. Default is 50 MiB.
+	MaxResponseBodySize int64
+
+	// StripHeaders is a list of additional request headers that are removed before a request is
+	// forwarded. Hop-by-hop headers, cookies and forwarding headers (e.g., X-Forwarded-For)
+	// are always removed. The Authorization header that is sent to the proxy is removed as well,
+	// because it may contain the credentials for WithAuthorizer. Swagger UI sends the Authorization
+	// header for the API in the X-Try-It-Out-Authorization header instead.
+	StripHeaders []string
+
+	// Transport is used to send the proxied requests. Default is http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Credentials is called for every proxied request right before it is sent to the upstream server.
+	// It can be used to attach credentials (e.g., an Authorization header) on the server side, so that
+	// secrets never reach the browser. The request URL contains the upstream URL, so credentials can
+	// be chosen per host. Headers set by this function override headers sent by the browser.
+	// See BearerTokenCredentials for a ready-made implementation.
+	Credentials func(r *http.Request) error
+}
+
+// TokenSource provides tokens that are attached to proxied "Try it out" requests.
+// See BearerTokenCredentials.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc is an adapter to allow the use of ordinary functions as TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// BearerTokenCredentials returns a function for TryItOutProxy.Credentials that sets the
+// Authorization header of every proxied request to a bearer token from the given TokenSource.
+func BearerTokenCredentials(source TokenSource) func(r *http.Request) error {
+	return func(r *http.Request) error {
+		token, err := source.Token(r.Context())
+		if err != nil {
+			return fmt.Errorf("cannot get token: %w", err)
+		}
+
+		r.Header.Set("Authorization", "Bearer "+token)
+
+		return nil
+	}
+}
+
+// credentialsTransport attaches credentials to requests before they are sent.
+type credentialsTransport struct {
+	base        http.RoundTripper
+	credentials func(r *http.Request) error
+}
+
+func (t *credentialsTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request it receives.
+	r = r.Clone(r.Context())
+
+	if err := t.credentials(r); err != nil {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		return nil, fmt.Errorf("cannot attach credentials: %w", err)
+	}
+
+	return t.base.RoundTrip(r)
+}
+
+// WithTryItOutProxy enables a same-origin proxy endpoint that all "Try it out" requests
+// to other origins are sent through. This allows to use "Try it out" with APIs that do not
+// send CORS headers. Requests are only forwarded to hosts of the servers listed in the spec,
+// to the server URLs of WithServerRewrite and to TryItOutProxy.AllowedHosts. Specs that are loaded by Swagger UI from a URL
+// (see WithSpecURL and WithSpecURLs) are not known to the handler, so all hosts need to
+// be configured in TryItOutProxy.AllowedHosts or TryItOutProxy.AllowedHostsFunc in this case.
+//
+// The proxy only accepts requests that Swagger UI sends from the origin it is served from, so that other
+// sites cannot use it to send requests with credentials (see TryItOutProxy.Credentials and WithAuthorizer).
+func WithTryItOutProxy(proxy TryItOutProxy) Option {
+	return func(cfg *uiConfig) {
+		if proxy.Timeout <= 0 {
+			proxy.Timeout = 30 * time.Second
+		}
+
+		if proxy.MaxRequestBodySize <= 0 {
+			proxy.MaxRequestBodySize = 10 << 20
+		}
+
+		if proxy.MaxResponseBodySize <= 0 {
+			proxy.MaxResponseBodySize = 50 << 20
+		}
+
+		if proxy.Transport == nil {
+			proxy.Transport = http.DefaultTransport
+		}
+
+		cfg.tryItOutProxy = configValue[TryItOutProxy]{Value: proxy, IsSet: true}
+	}
+}
+
+// strippedRequestHeaders are removed from proxied requests in addition to hop-by-hop headers,
+// which are removed by httputil.ReverseProxy. Credentials, cookies and forwarding headers belong
+// to the origin Swagger UI is served from and must not be sent to the API.
+var strippedRequestHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Forwarded",
+	"X-Forwarded-For",
+	"X-Forwarded-Host",
+	"X-Forwarded-Proto",
+	"X-Real-Ip",
+	"Origin",
+	"Referer",
+}
+
+// proxyAuthorizationHeader carries the Authorization header for the API. Swagger UI sends it instead
+// of the Authorization header, which belongs to the origin Swagger UI is served from (e.g., credentials
+// for WithAuthorizer that the browser attaches to all same-origin requests).
+const proxyAuthorizationHeader = "X-Try-It-Out-Authorization"
+
+// proxyRequestHeader must be set on all requests to the proxy. Cross-origin pages cannot send custom
+// headers without a CORS preflight request, which the proxy does not allow, and HTML forms cannot send
+// them at all. Together with the origin check, this prevents other sites from sending requests through
+// the proxy with the credentials of the user or of TryItOutProxy.Credentials.
+const proxyRequestHeader = "X-Try-It-Out-Proxy"
+
+// strippedResponseHeaders are removed from proxied responses, because they would
+// otherwise apply to the origin Swagger UI is served from.
+var strippedResponseHeaders = []string{
+	"Set-Cookie",
+	"Strict-Transport-Security",
+	"Alt-Svc",
+}
+
+type proxyTargetKey struct{}
+
+type tryItOutProxy struct {
+	cfg          *uiConfig
+	reverseProxy *httputil.ReverseProxy
+
+	mu           sync.Mutex
+	hostsSpec    []byte
+	allowedHosts map[string]struct{}
+}
+
+func newTryItOutProxy(cfg *uiConfig) *tryItOutProxy {
+	settings := cfg.tryItOutProxy.Value
+
+	transport := settings.Transport
+	if settings.Credentials != nil {
+		transport = &credentialsTransport{base: transport, credentials: settings.Credentials}
+	}
+
+	p := &tryItOutProxy{cfg: cfg}
+	p.reverseProxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			target := r.In.Context().Value(proxyTargetKey{}).(*url.URL)
+
+			r.Out.URL = target
+			r.Out.Host = target.Host
+
+			for _, header := range strippedRequestHeaders {
+				r.Out.Header.Del(header)
+			}
+
+			if authorization := r.In.Header.Get(proxyAuthorizationHeader); authorization != "" {
+				r.Out.Header.Set("Authorization", authorization)
+			}
+			r.Out.Header.Del(proxyAuthorizationHeader)
+			r.Out.Header.Del(proxyRequestHeader)
+
+			for _, header := range settings.StripHeaders {
+				r.Out.Header.Del(header)
+			}
+		},
+		Transport: transport,
+		ModifyResponse: func(resp *http.Response) error {
+			if resp.ContentLength > settings.MaxResponseBodySize {
+				return errResponseTooLarge
+			}
+
+			for _, header := range strippedResponseHeaders {
+				resp.Header.Del(header)
+			}
+
+			resp.Body = &limitedReadCloser{ReadCloser: resp.Body, remaining: settings.MaxResponseBodySize}
+
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			var maxBytesErr *http.MaxBytesError
+
+			status := http.StatusBadGateway
+			switch {
+			case errors.As(err, &maxBytesErr):
+				status = http.StatusRequestEntityTooLarge
+			case errors.Is(err, context.DeadlineExceeded):
+				status = http.StatusGatewayTimeout
+			}
+
+			slog.Error("failed to proxy request", "err", err.Error())
+			sendStatus(w, status)
+		},
+	}
+
+	return p
+}
+
+func (p *tryItOutProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	settings := p.cfg.tryItOutProxy.Value
+
+	sameOrigin := isSameOriginRequest(r, p.cfg.serverRewrite.Value.TrustForwardedHeaders)
+	if r.Header.Get(proxyRequestHeader) == "" || !sameOrigin {
+		slog.Warn("refusing to proxy cross-origin request", "origin", r.Header.Get("Origin"))
+		sendStatus(w, http.StatusForbidden)
+		return
+	}
+
+	target, err := url.Parse(r.URL.Query().Get("url"))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		sendStatus(w, http.StatusBadRequest)
+		return
+	}
+
+	if !p.isAllowed(target) {
+		slog.Warn("refusing to proxy request to host that is not allowed", "host", target.Host)
+		sendStatus(w, http.StatusForbidden)
+		return
+	}
+
+	if r.ContentLength > settings.MaxRequestBodySize {
+		sendStatus(w, http.StatusRequestEntityTooLarge)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, settings.MaxRequestBodySize)
+
+	ctx, cancel := context.WithTimeout(r.Context(), settings.Timeout)
+	defer cancel()
+
+	ctx = context.WithValue(ctx, proxyTargetKey{}, target)
+
+	p.reverseProxy.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// isSameOriginRequest reports whether the request was sent by a page of the origin Swagger UI is
+// served from. Browsers that do not send the Sec-Fetch-Site header send the Origin header instead.
+func isSameOriginRequest(r *http.Request, trustForwardedHeaders bool) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site == "same-origin"
+	}
+
+	origin, err := url.Parse(r.Header.Get("Origin"))
+	if err != nil || origin.Host == "" {
+		return false
+	}
+
+	_, host := requestOrigin(r, trustForwardedHeaders)
+	return strings.EqualFold(origin.Host, host)
+}
+
+// isAllowed reports whether requests may be forwarded to the target URL.
+func (p *tryItOutProxy) isAllowed(target *url.URL) bool {
+	allowed := p.hosts()
+	if hostsFunc := p.cfg.tryItOutProxy.Value.AllowedHostsFunc; hostsFunc != nil {
+		extra := hostsFunc()
+		merged := make(map[string]struct{}, len(allowed)+len(extra))
+		for host := range allowed {
+			merged[host] = struct{}{}
+		}
+		for _, host := range extra {
+			merged[host] = struct{}{}
+		}
+		allowed = merged
+	}
+
+	if _, ok := allowed[target.Host]; ok {
+		return true
+	}
+
+	// Allow "api.example.com" to match "api.example.com:443" for HTTPS (and port 80 for HTTP).
+	hostname, port, err := net.SplitHostPort(target.Host)
+	if err == nil && ((target.Scheme == "https" && port == "443") || (target.Scheme == "http" && port == "80")) {
+		_, ok := allowed[hostname]
+		return ok
+	}
+
+	return false
+}
+
+// hosts returns all allowed hosts. Hosts are derived from the spec and the server rewrite
+// mapping and recalculated whenever the spec changes (see WithSpecFilePath).
+func (p *tryItOutProxy) hosts() map[string]struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	currentSpec := p.cfg.currentSpec()
+	if p.allowedHosts != nil && sameBytes(p.hostsSpec, currentSpec) {
+		return p.allowedHosts
+	}
+
+	hosts := toSet(p.cfg.tryItOutProxy.Value.AllowedHosts)
+
+	// Servers rewritten by WithServerRewrite are not contained in the prepared spec.
+	for _, urls := range p.cfg.serverRewrite.Value.Mapping {
+		for _, rawURL := range urls {
+			if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+				hosts[u.Host] = struct{}{}
+			}
+		}
+	}
+
+	if len(currentSpec) > 0 {
+		spec, err := parseSpec(currentSpec)
+		if err != nil {
+			slog.Error("cannot determine allowed proxy hosts from spec", "err", err.Error())
+		} else {
+			for _, host := range specHosts(spec) {
+				hosts[host] = struct{}{}
+			}
+		}
+	}
+
+	p.hostsSpec = currentSpec
+	p.allowedHosts = hosts
+
+	return hosts
+}
+
+// SpecHosts returns the hosts of all servers and OAuth2 token URLs of a Swagger 2.0 or OpenAPI 3 spec
+// in YAML or JSON format. These are the hosts that TryItOutProxy allows for the spec of the handler.
+func SpecHosts(spec []byte) ([]string, error) {
+	jsonSpec, err := yamlOrJSONToJSON(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseSpec(jsonSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return specHosts(parsed), nil
+}
+
+// specHosts returns the hosts of all servers and OAuth2 token URLs of a spec.
+func specHosts(spec map[string]any) []string {
+	var urls []string
+
+	if isSwagger2(spec) {
+		if host, ok := spec["host"].(string); ok {
+			urls = append(urls, "http://"+host)
+		}
+	} else {
+		forEachServerList(spec, func(servers []any) {
+			for _, server := range servers {
+				if serverObj, ok := server.(map[string]any); ok {
+					urls = append(urls, expandServerURL(serverObj)...)
+				}
+			}
+		})
+	}
+
+	walkObjects(spec, func(obj map[string]any) {
+		for _, key := range []string{"tokenUrl", "refreshUrl"} {
+			if tokenURL, ok := obj[key].(string); ok {
+				urls = append(urls, tokenURL)
+			}
+		}
+	})
+
+	var hosts []string
+	for _, rawURL := range urls {
+		if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+			hosts = append(hosts, u.Host)
+		}
+	}
+
+	return hosts
+}
+
+// maxServerURLExpansions limits the number of URLs a server URL with variables is expanded into.
+const maxServerURLExpansions = 100
+
+// expandServerURL returns all URLs that can be built from an OpenAPI 3 server object by
+// substituting its variables with their enum values (or default value, if there is no enum).
+func expandServerURL(server map[string]any) []string {
+	serverURL, _ := server["url"].(string)
+	urls := []string{serverURL}
+
+	variables, _ := server["variables"].(map[string]any)
+	for _, name := range sortedKeys(variables) {
+		variable, _ := variables[name].(map[string]any)
+
+		var values []string
+		if enum, ok := variable["enum"].([]any); ok {
+			for _, value := range enum {
+				values = append(values, fmt.Sprint(value))
+			}
+		} else if value, ok := variable["default"]; ok {
+			values = append(values, fmt.Sprint(value))
+		}
+
+		var expanded []string
+		for _, u := range urls {
+			for _, value := range values {
+				if len(expanded) < maxServerURLExpansions {
+					expanded = append(expanded, strings.ReplaceAll(u, "{"+name+"}", value))
+				}
+			}
+		}
+
+		if len(expanded) > 0 {
+			urls = expanded
+		}
+	}
+
+	return urls
+}
+
+var errResponseTooLarge = errors.New("response body too large")
+
+// limitedReadCloser returns an error after more than remaining bytes have been read.
+type limitedReadCloser struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errResponseTooLarge
+	}
+
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return 0, errResponseTooLarge
+	}
+
+	return n, err
+}
@@ -0,0 +1,289 @@
+package go_swagger_ui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+// newProxyTestHandler returns a handler that serves testSpec with the "Try it out" proxy enabled.
+func newProxyTestHandler(proxy TryItOutProxy, options ...Option) http.Handler {
+	options = append([]Option{WithSpec([]byte(testSpec)), WithTryItOutProxy(proxy)}, options...)
+	return NewHandler(options...)
+}
+
+// proxyRequest returns a request to the proxy endpoint that forwards to the target URL.
+// The request has the headers Swagger UI sends from the origin it is served from.
+func proxyRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/_proxy?url="+url.QueryEscape(target), nil)
+	req.Header.Set("Sec-Fetch-Site", "same-origin")
+	req.Header.Set(proxyRequestHeader, "true")
+	return req
+}
+
+func TestTryItOutProxyAllowsServerRewriteTargets(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	handler := newProxyTestHandler(TryItOutProxy{}, WithServerRewrite(ServerRewrite{
+		Mapping: map[string][]string{"docs.staging.example.com": {upstream.URL + "/v1"}},
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, proxyRequest(upstream.URL+"/v1/pets"))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestTryItOutProxyRefusesUnknownHosts(t *testing.T) {
+	handler := newProxyTestHandler(TryItOutProxy{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, proxyRequest("https://evil.example.com/pets"))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestTryItOutProxyRejectsCrossOriginRequests(t *testing.T) {
+	var calls, credentialCalls int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get(proxyRequestHeader) != "" {
+			t.Errorf("expected the %s header to be removed", proxyRequestHeader)
+		}
+	}))
+	defer upstream.Close()
+
+	handler := newProxyTestHandler(TryItOutProxy{
+		AllowedHosts: []string{upstreamHost(upstream)},
+		Credentials: func(r *http.Request) error {
+			credentialCalls++
+			r.Header.Set("Authorization", "Bearer server-token")
+			return nil
+		},
+	}, WithAuthorizer(BasicAuth("docs", map[string]string{"admin": "secret"})))
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{"same origin", map[string]string{"Sec-Fetch-Site": "same-origin", proxyRequestHeader: "true"}, http.StatusOK},
+		{"matching origin", map[string]string{"Origin": "http://example.com", proxyRequestHeader: "true"}, http.StatusOK},
+		{"cross-site", map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "https://evil.example.com", proxyRequestHeader: "true"}, http.StatusForbidden},
+		{"same site", map[string]string{"Sec-Fetch-Site": "same-site", "Origin": "http://other.example.com", proxyRequestHeader: "true"}, http.StatusForbidden},
+		{"other origin", map[string]string{"Origin": "https://evil.example.com", proxyRequestHeader: "true"}, http.StatusForbidden},
+		{"origin with the request host as prefix", map[string]string{"Origin": "http://example.com.evil.io", proxyRequestHeader: "true"}, http.StatusForbidden},
+		{"no origin", map[string]string{proxyRequestHeader: "true"}, http.StatusForbidden},
+		{"missing proxy header", map[string]string{"Sec-Fetch-Site": "same-origin"}, http.StatusForbidden},
+		{"cross-site form", map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "https://evil.example.com"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, credentialCalls = 0, 0
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/_proxy?url="+url.QueryEscape(upstream.URL+"/pets"), nil)
+			req.SetBasicAuth("admin", "secret")
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			expectedCalls := 0
+			if tt.expectedStatus == http.StatusOK {
+				expectedCalls = 1
+			}
+			if calls != expectedCalls || credentialCalls != expectedCalls {
+				t.Errorf("expected %d upstream and credential calls, got %d and %d", expectedCalls, calls, credentialCalls)
+			}
+		})
+	}
+}
+
+func TestTryItOutProxyDoesNotForwardDocsCredentials(t *testing.T) {
+	var authorization string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer upstream.Close()
+
+	handler := newProxyTestHandler(
+		TryItOutProxy{AllowedHosts: []string{upstreamHost(upstream)}},
+		WithAuthorizer(BasicAuth("docs", map[string]string{"admin": "secret"})),
+	)
+
+	tests := []struct {
+		name, apiAuthorization, expected string
+	}{
+		{name: "without API credentials", expected: ""},
+		{name: "with API credentials", apiAuthorization: "Bearer api-token", expected: "Bearer api-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorization = ""
+
+			req := proxyRequest(upstream.URL + "/pets")
+			req.SetBasicAuth("admin", "secret")
+			if tt.apiAuthorization != "" {
+				req.Header.Set(proxyAuthorizationHeader, tt.apiAuthorization)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+			}
+			if authorization != tt.expected {
+				t.Errorf("expected Authorization header %q upstream, got %q", tt.expected, authorization)
+			}
+		})
+	}
+}
+
+func upstreamHost(upstream *httptest.Server) string {
+	u, _ := url.Parse(upstream.URL)
+	return u.Host
+}
+
+func TestTryItOutProxyCredentials(t *testing.T) {
+	var calls int
+	var authorization string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name                  string
+		token                 func(ctx context.Context) (string, error)
+		browserAuthorization  string
+		expectedStatus        int
+		expectedCalls         int
+		expectedAuthorization string
+	}{
+		{
+			name:                  "injects token",
+			token:                 func(ctx context.Context) (string, error) { return "server-token", nil },
+			expectedStatus:        http.StatusOK,
+			expectedCalls:         1,
+			expectedAuthorization: "Bearer server-token",
+		},
+		{
+			name:                  "replaces browser header",
+			token:                 func(ctx context.Context) (string, error) { return "server-token", nil },
+			browserAuthorization:  "Bearer browser-token",
+			expectedStatus:        http.StatusOK,
+			expectedCalls:         1,
+			expectedAuthorization: "Bearer server-token",
+		},
+		{
+			name:           "fails without calling upstream",
+			token:          func(ctx context.Context) (string, error) { return "", errors.New("token expired") },
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, authorization = 0, ""
+
+			handler := newProxyTestHandler(TryItOutProxy{
+				AllowedHosts: []string{upstreamHost(upstream)},
+				Credentials:  BearerTokenCredentials(TokenSourceFunc(tt.token)),
+			})
+
+			req := proxyRequest(upstream.URL + "/pets")
+			if tt.browserAuthorization != "" {
+				req.Header.Set(proxyAuthorizationHeader, tt.browserAuthorization)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d upstream calls, got %d", tt.expectedCalls, calls)
+			}
+			if authorization != tt.expectedAuthorization {
+				t.Errorf("expected Authorization header %q upstream, got %q", tt.expectedAuthorization, authorization)
+			}
+		})
+	}
+}
+
+func TestTryItOutProxyAllowedHostsFunc(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	var hosts []string
+	handler := newProxyTestHandler(TryItOutProxy{AllowedHostsFunc: func() []string { return hosts }})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, proxyRequest(upstream.URL+"/pets"))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d before the host is allowed, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	hosts = []string{upstreamHost(upstream)}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, proxyRequest(upstream.URL+"/pets"))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d after the host is allowed, got %d", http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestSpecHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected []string
+	}{
+		{
+			name:     "OpenAPI 3",
+			spec:     testSpec,
+			expected: []string{"api.example.com"},
+		},
+		{
+			name:     "Swagger 2.0",
+			spec:     "swagger: \"2.0\"\ninfo: {title: Pets, version: \"1\"}\nhost: api.example.com:8443\npaths: {}\n",
+			expected: []string{"api.example.com:8443"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hosts, err := SpecHosts([]byte(tt.spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(hosts, tt.expected) {
+				t.Errorf("expected hosts %v, got %v", tt.expected, hosts)
+			}
+		})
+	}
+}
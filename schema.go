@@ -0,0 +1,427 @@
+package go_swagger_ui
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxSampleDepth limits the number of references that are followed when generating
+// sample values for deeply nested schemas.
+const maxSampleDepth = 8
+
+// schemaSample generates a sample value for a schema. Examples, default values and enum values
+// defined in the schema are preferred over generated values.
+func schemaSample(spec map[string]any, schema any) any {
+	return sampleValue(spec, schema, nil)
+}
+
+// sampleValue generates a sample value for a schema. The refs slice contains all references that
+// have been followed to reach the schema. Recursive references are not followed again.
+func sampleValue(spec map[string]any, schema any, refs []string) any {
+	if obj, ok := schema.(map[string]any); ok {
+		if ref, ok := obj["$ref"].(string); ok {
+			if slices.Contains(refs, ref) {
+				return nil
+			}
+			refs = append(refs[:len(refs):len(refs)], ref)
+		}
+	}
+
+	s := deref(spec, schema)
+	if s == nil || len(refs) > maxSampleDepth {
+		return nil
+	}
+
+	if example, ok := s["example"]; ok {
+		return example
+	}
+
+	if examples, ok := s["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+
+	for _, key := range []string{"const", "default"} {
+		if value, ok := s[key]; ok {
+			return value
+		}
+	}
+
+	if enum, ok := s["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf, ok := s["allOf"].([]any); ok {
+		merged := make(map[string]any)
+		for _, subSchema := range allOf {
+			if sample, ok := sampleValue(spec, subSchema, refs).(map[string]any); ok {
+				for key, value := range sample {
+					merged[key] = value
+				}
+			}
+		}
+		for key, value := range objectSample(spec, s, refs) {
+			merged[key] = value
+		}
+		return merged
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if subSchemas, ok := s[key].([]any); ok && len(subSchemas) > 0 {
+			return sampleValue(spec, subSchemas[0], refs)
+		}
+	}
+
+	switch schemaType(s) {
+	case "object":
+		return objectSample(spec, s, refs)
+	case "array":
+		item := sampleValue(spec, s["items"], refs)
+		if item == nil {
+			return []any{}
+		}
+		return []any{item}
+	case "string":
+		return stringSample(s)
+	case "integer":
+		if minimum, ok := s["minimum"].(float64); ok {
+			return math.Ceil(minimum)
+		}
+		return 0
+	case "number":
+		if minimum, ok := s["minimum"].(float64); ok {
+			return minimum
+		}
+		return 0.0
+	case "boolean":
+		return true
+	default:
+		return nil
+	}
+}
+
+func objectSample(spec map[string]any, s map[string]any, refs []string) map[string]any {
+	sample := make(map[string]any)
+
+	properties, _ := s["properties"].(map[string]any)
+	for name, property := range properties {
+		// Write-only properties are only used in requests, never in responses.
+		if writeOnly, _ := deref(spec, property)["writeOnly"].(bool); writeOnly {
+			continue
+		}
+
+		if value := sampleValue(spec, property, refs); value != nil {
+			sample[name] = value
+		}
+	}
+
+	return sample
+}
+
+func stringSample(s map[string]any) string {
+	format, _ := s["format"].(string)
+
+	switch format {
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T12:00:00Z"
+	case "time":
+		return "12:00:00"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.168.0.1"
+	case "ipv6":
+		return "::1"
+	case "byte":
+		return "c3RyaW5n"
+	case "binary":
+		return ""
+	default:
+		return "string"
+	}
+}
+
+// schemaType returns the type of a schema. For OpenAPI 3.1 schemas with multiple types,
+// the first type that is not "null" is returned. The type is derived from the presence
+// of properties or items if not explicitly set.
+func schemaType(s map[string]any) string {
+	switch value := s["type"].(type) {
+	case string:
+		return value
+	case []any:
+		for _, elem := range value {
+			if t, ok := elem.(string); ok && t != "null" {
+				return t
+			}
+		}
+	}
+
+	if _, ok := s["properties"]; ok {
+		return "object"
+	}
+
+	if _, ok := s["items"]; ok {
+		return "array"
+	}
+
+	return ""
+}
+
+// validateValue validates a value against a schema and returns a description of every violation.
+// The value is expected to be the result of unmarshalling JSON into an any value.
+func validateValue(spec map[string]any, schema any, value any, path string) []string {
+	s := deref(spec, schema)
+	if s == nil {
+		return nil
+	}
+
+	if value == nil && allowsNull(s) {
+		return nil
+	}
+
+	var problems []string
+
+	if allOf, ok := s["allOf"].([]any); ok {
+		for _, subSchema := range allOf {
+			problems = append(problems, validateValue(spec, subSchema, value, path)...)
+		}
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		subSchemas, ok := s[key].([]any)
+		if !ok {
+			continue
+		}
+
+		matches := 0
+		for _, subSchema := range subSchemas {
+			if len(validateValue(spec, subSchema, value, path)) == 0 {
+				matches++
+			}
+		}
+
+		if matches == 0 || (key == "oneOf" && matches > 1) {
+			problems = append(problems, fmt.Sprintf("%s: does not match exactly one schema of %s", path, key))
+		}
+	}
+
+	if enum, ok := s["enum"].([]any); ok && !containsValue(enum, value) {
+		problems = append(problems, fmt.Sprintf("%s: must be one of %v", path, enum))
+	}
+
+	if problem := checkType(s, value, path); problem != "" {
+		return append(problems, problem)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		problems = append(problems, validateObject(spec, s, v, path)...)
+	case []any:
+		if items, ok := s["items"]; ok {
+			for idx, item := range v {
+				problems = append(problems, validateValue(spec, items, item, fmt.Sprintf("%s[%d]", path, idx))...)
+			}
+		}
+		if minItems, ok := s["minItems"].(float64); ok && float64(len(v)) < minItems {
+			problems = append(problems, fmt.Sprintf("%s: must contain at least %v items", path, minItems))
+		}
+		if maxItems, ok := s["maxItems"].(float64); ok && float64(len(v)) > maxItems {
+			problems = append(problems, fmt.Sprintf("%s: must contain at most %v items", path, maxItems))
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if minLength, ok := s["minLength"].(float64); ok && length < minLength {
+			problems = append(problems, fmt.Sprintf("%s: must be at least %v characters long", path, minLength))
+		}
+		if maxLength, ok := s["maxLength"].(float64); ok && length > maxLength {
+			problems = append(problems, fmt.Sprintf("%s: must be at most %v characters long", path, maxLength))
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				problems = append(problems, fmt.Sprintf("%s: must match pattern %q", path, pattern))
+			}
+		}
+	case float64:
+		problems = append(problems, validateNumber(s, v, path)...)
+	}
+
+	return problems
+}
+
+func validateObject(spec map[string]any, s map[string]any, value map[string]any, path string) []string {
+	var problems []string
+
+	required, _ := s["required"].([]any)
+	for _, name := range required {
+		if _, ok := value[fmt.Sprint(name)]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]any)
+	for _, name := range sortedKeys(value) {
+		property, ok := properties[name]
+		if ok {
+			problems = append(problems, validateValue(spec, property, value[name], path+"."+name)...)
+			continue
+		}
+
+		switch additional := s["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				problems = append(problems, fmt.Sprintf("%s: unknown property %q", path, name))
+			}
+		case map[string]any:
+			problems = append(problems, validateValue(spec, additional, value[name], path+"."+name)...)
+		}
+	}
+
+	return problems
+}
+
+func validateNumber(s map[string]any, value float64, path string) []string {
+	var problems []string
+
+	if minimum, ok := s["minimum"].(float64); ok {
+		exclusive, _ := s["exclusiveMinimum"].(bool)
+		if value < minimum || (exclusive && value == minimum) {
+			problems = append(problems, fmt.Sprintf("%s: must not be less than %v", path, minimum))
+		}
+	}
+
+	// OpenAPI 3.1 uses numeric exclusive bounds.
+	if exclusiveMinimum, ok := s["exclusiveMinimum"].(float64); ok && value <= exclusiveMinimum {
+		problems = append(problems, fmt.Sprintf("%s: must be greater than %v", path, exclusiveMinimum))
+	}
+
+	if maximum, ok := s["maximum"].(float64); ok {
+		exclusive, _ := s["exclusiveMaximum"].(bool)
+		if value > maximum || (exclusive && value == maximum) {
+			problems = append(problems, fmt.Sprintf("%s: must not be greater than %v", path, maximum))
+		}
+	}
+
+	if exclusiveMaximum, ok := s["exclusiveMaximum"].(float64); ok && value >= exclusiveMaximum {
+		problems = append(problems, fmt.Sprintf("%s: must be less than %v", path, exclusiveMaximum))
+	}
+
+	return problems
+}
+
+func checkType(s map[string]any, value any, path string) string {
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, elem := range t {
+			types = append(types, fmt.Sprint(elem))
+		}
+	default:
+		return ""
+	}
+
+	for _, t := range types {
+		if hasType(t, value) {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("%s: must be of type %s", path, strings.Join(types, " or "))
+}
+
+func hasType(t string, value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return t == "null"
+	case map[string]any:
+		return t == "object"
+	case []any:
+		return t == "array"
+	case string:
+		return t == "string"
+	case bool:
+		return t == "boolean"
+	case float64:
+		return t == "number" || (t == "integer" && v == math.Trunc(v))
+	default:
+		return false
+	}
+}
+
+func allowsNull(s map[string]any) bool {
+	if nullable, _ := s["nullable"].(bool); nullable {
+		return true
+	}
+
+	// Swagger 2 vendor extension for nullable values.
+	if nullable, _ := s["x-nullable"].(bool); nullable {
+		return true
+	}
+
+	types, _ := s["type"].([]any)
+	return containsValue(types, "null")
+}
+
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseParameterValue converts the raw values of a request parameter (e.g., a query parameter) into a
+// value of the type declared by the schema, so that it can be validated with validateValue. Values that
+// cannot be converted are returned as strings, so that validation reports a type error.
+func parseParameterValue(spec map[string]any, schema any, raw []string) any {
+	s := deref(spec, schema)
+
+	if schemaType(s) == "array" {
+		var values []string
+		for _, value := range raw {
+			values = append(values, strings.Split(value, ",")...)
+		}
+
+		items := make([]any, 0, len(values))
+		for _, value := range values {
+			items = append(items, parseScalarValue(deref(spec, s["items"]), value))
+		}
+		return items
+	}
+
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return parseScalarValue(s, raw[0])
+}
+
+func parseScalarValue(s map[string]any, raw string) any {
+	switch schemaType(s) {
+	case "integer", "number":
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			return value
+		}
+	case "boolean":
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	}
+
+	return raw
+}
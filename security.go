@@ -0,0 +1,215 @@
+package go_swagger_ui
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SecurityHeaders configures the security related HTTP response headers that are sent
+// along with Swagger UI files. See WithSecurityHeaders.
+type SecurityHeaders struct {
+	// ContentSecurityPolicy enables the Content-Security-Policy header. When enabled, a new nonce is
+	// generated for every index.html and oauth2-redirect.html request and added to all script and
+	// stylesheet tags. The policy
+	// that is sent looks like this (values in angle brackets depend on the configuration):
+	//
+	//	default-src 'none';
+	//	script-src 'self' 'nonce-<nonce>';
+	//	style-src 'self' 'nonce-<nonce>';
+	//	img-src 'self' data: <validator origin> <ImageSources>;
+	//	font-src 'self' data:;
+	//	connect-src 'self' <spec URL origins> <ConnectSources>;
+	//	form-action 'self';
+	//	base-uri 'self';
+	//	frame-ancestors <FrameAncestors>
+	//
+	// This policy works with all Swagger UI features, as long as all hosts that are contacted
+	// by "Try it out" or by OAuth2 token requests are listed in ConnectSources.
+	ContentSecurityPolicy bool
+
+	// ConnectSources is a list of additional sources (e.g., "https://api.example.com") that Swagger UI
+	// is allowed to connect to. Usually, these are the servers listed in the spec that are used by
+	// "Try it out" and OAuth2 token endpoints. Origins of spec URLs configured with WithSpecURL,
+	// WithSpecURLs and WithConfigURL are added automatically.
+	ConnectSources []string
+
+	// ImageSources is a list of additional sources that images may be loaded from. The origin of the
+	// validator URL set with WithValidatorURL (or swagger.io's online validator, if not disabled)
+	// is added automatically.
+	ImageSources []string
+
+	// FrameAncestors is a list of sources that may embed Swagger UI in a frame. If the list is empty,
+	// framing is forbidden entirely by sending "frame-ancestors 'none'" and "X-Frame-Options: DENY".
+	FrameAncestors []string
+
+	// ReferrerPolicy sets the Referrer-Policy header (e.g., "no-referrer",
+	// "strict-origin-when-cross-origin"). The header is not sent if left empty.
+	ReferrerPolicy string
+
+	// NoSniff sets the header "X-Content-Type-Options: nosniff".
+	NoSniff bool
+}
+
+// DefaultSecurityHeaders returns a strict security header configuration, which enables
+// the Content-Security-Policy header, forbids framing, disables content type sniffing
+// and does not send referrer information.
+func DefaultSecurityHeaders() SecurityHeaders {
+	return SecurityHeaders{
+		ContentSecurityPolicy: true,
+		ReferrerPolicy:        "no-referrer",
+		NoSniff:               true,
+	}
+}
+
+// WithSecurityHeaders enables sending security related HTTP response headers, such as
+// Content-Security-Policy, X-Content-Type-Options, Referrer-Policy and X-Frame-Options.
+// Use DefaultSecurityHeaders for a strict default configuration.
+func WithSecurityHeaders(headers SecurityHeaders) Option {
+	return func(cfg *uiConfig) {
+		cfg.securityHeaders = configValue[SecurityHeaders]{Value: headers, IsSet: true}
+	}
+}
+
+// setSecurityHeaders sets all configured security headers on the response. The nonce is
+// only used for the Content-Security-Policy header and may be empty for responses that
+// do not contain HTML.
+func setSecurityHeaders(w http.ResponseWriter, cfg *uiConfig, nonce string) {
+	if !cfg.securityHeaders.IsSet {
+		return
+	}
+
+	headers := cfg.securityHeaders.Value
+
+	if headers.NoSniff {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+	}
+
+	if headers.ReferrerPolicy != "" {
+		w.Header().Set("Referrer-Policy", headers.ReferrerPolicy)
+	}
+
+	if len(headers.FrameAncestors) == 0 {
+		w.Header().Set("X-Frame-Options", "DENY")
+	}
+
+	if headers.ContentSecurityPolicy {
+		w.Header().Set("Content-Security-Policy", contentSecurityPolicy(cfg, nonce))
+	}
+}
+
+func contentSecurityPolicy(cfg *uiConfig, nonce string) string {
+	headers := cfg.securityHeaders.Value
+
+	scriptSources := []string{"'self'"}
+	styleSources := []string{"'self'"}
+	if nonce != "" {
+		scriptSources = append(scriptSources, "'nonce-"+nonce+"'")
+		styleSources = append(styleSources, "'nonce-"+nonce+"'")
+	}
+
+	imageSources := []string{"'self'", "data:"}
+	if origin := validatorOrigin(cfg); origin != "" {
+		imageSources = append(imageSources, origin)
+	}
+	imageSources = append(imageSources, headers.ImageSources...)
+
+	connectSources := []string{"'self'"}
+	for _, specURL := range specURLs(cfg) {
+		if origin := originOf(specURL); origin != "" {
+			connectSources = append(connectSources, origin)
+		}
+	}
+	connectSources = append(connectSources, headers.ConnectSources...)
+
+	frameAncestors := headers.FrameAncestors
+	if len(frameAncestors) == 0 {
+		frameAncestors = []string{"'none'"}
+	}
+
+	directives := []string{
+		"default-src 'none'",
+		"script-src " + joinSources(scriptSources),
+		"style-src " + joinSources(styleSources),
+		"img-src " + joinSources(imageSources),
+		"font-src 'self' data:",
+		"connect-src " + joinSources(connectSources),
+		"form-action 'self'",
+		"base-uri 'self'",
+		"frame-ancestors " + joinSources(frameAncestors),
+	}
+
+	return strings.Join(directives, "; ")
+}
+
+// validatorOrigin returns the origin of the validator that Swagger UI loads its
+// validator badge from. Swagger UI uses swagger.io's online validator if no validator
+// URL has been configured.
+func validatorOrigin(cfg *uiConfig) string {
+	if cfg.localValidator.IsSet {
+		return ""
+	}
+
+	if !cfg.validatorUrl.IsSet {
+		return "https://validator.swagger.io"
+	}
+
+	return originOf(cfg.validatorUrl.Value)
+}
+
+// specURLs returns all URLs Swagger UI fetches specifications or configuration documents from.
+func specURLs(cfg *uiConfig) []string {
+	var urls []string
+
+	if cfg.url.IsSet {
+		urls = append(urls, cfg.url.Value)
+	}
+
+	if cfg.configURL.IsSet {
+		urls = append(urls, cfg.configURL.Value)
+	}
+
+	for _, specURL := range cfg.urls {
+		urls = append(urls, specURL.URL)
+	}
+
+	return urls
+}
+
+// originOf returns the origin (scheme and host) of an absolute URL. It returns an
+// empty string for relative URLs, because these are covered by 'self' already.
+func originOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
+func joinSources(sources []string) string {
+	seen := make(map[string]struct{}, len(sources))
+	unique := make([]string, 0, len(sources))
+
+	for _, source := range sources {
+		if _, ok := seen[source]; ok {
+			continue
+		}
+		seen[source] = struct{}{}
+		unique = append(unique, source)
+	}
+
+	return strings.Join(unique, " ")
+}
+
+func newNonce() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf[:]), nil
+}
@@ -0,0 +1,62 @@
+package go_swagger_ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var scriptTagPattern = regexp.MustCompile(`<script[^>]*>`)
+
+func TestContentSecurityPolicyAllowsOAuth2RedirectScript(t *testing.T) {
+	handler := NewHandler(
+		WithSpec([]byte(`{"openapi": "3.0.3", "info": {"title": "test", "version": "1"}, "paths": {}}`)),
+		WithSecurityHeaders(DefaultSecurityHeaders()),
+	)
+
+	for _, page := range []string{"/index.html", "/oauth2-redirect.html"} {
+		t.Run(page, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, page, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rec.Code)
+			}
+
+			policy := rec.Header().Get("Content-Security-Policy")
+			nonce := regexp.MustCompile(`script-src [^;]*'nonce-([^']+)'`).FindStringSubmatch(policy)
+			if nonce == nil {
+				t.Fatalf("expected a script nonce in policy %q", policy)
+			}
+
+			scripts := scriptTagPattern.FindAllString(rec.Body.String(), -1)
+			if len(scripts) == 0 {
+				t.Fatal("expected the page to contain scripts")
+			}
+
+			// Scripts are allowed by the nonce only, neither inline scripts nor 'unsafe-inline' are allowed otherwise.
+			for _, script := range scripts {
+				if !strings.Contains(script, `nonce="`+nonce[1]+`"`) {
+					t.Errorf("script %s is blocked by policy %q", script, policy)
+				}
+			}
+		})
+	}
+}
+
+func TestContentSecurityPolicyUsesNewNonceForEveryResponse(t *testing.T) {
+	handler := NewHandler(WithSecurityHeaders(DefaultSecurityHeaders()))
+
+	policies := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth2-redirect.html", nil))
+		policies[rec.Header().Get("Content-Security-Policy")] = true
+	}
+
+	if len(policies) != 2 {
+		t.Errorf("expected different nonces for every response")
+	}
+}
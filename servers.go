@@ -0,0 +1,219 @@
+package go_swagger_ui
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ServerRewrite configures how the servers of a spec are rewritten for every request.
+// See WithServerRewrite.
+type ServerRewrite struct {
+	// Mapping maps request hosts (e.g., "docs.staging.example.com") to the server URLs that should be
+	// used for requests to this host (e.g., "https://api.staging.example.com/v1"). Keys may contain a port.
+	// If a request host is found in the mapping, all servers of the spec are replaced by the mapped URLs.
+	Mapping map[string][]string
+
+	// UseRequestHost replaces the scheme and host of all absolute server URLs with the scheme and host
+	// of the request, if the request host is not contained in Mapping. Server URL paths are kept.
+	UseRequestHost bool
+
+	// TrustForwardedHeaders determines the request scheme and host from the Forwarded,
+	// X-Forwarded-Proto and X-Forwarded-Host headers. Only enable this option if the handler is
+	// running behind a reverse proxy that sets these headers.
+	TrustForwardedHeaders bool
+}
+
+// WithServerRewrite rewrites the servers of the spec for every request, so that "Try it out"
+// sends requests to a server that matches the host the documentation is being viewed on
+// (e.g., a staging server instead of the production server that is listed in the spec).
+// For OpenAPI 3 specs the "servers" lists are rewritten, for Swagger 2 specs the "host",
+// "basePath" and "schemes" fields.
+// This option only has an effect on specs that are embedded into Swagger UI (see WithSpec and WithSpecFilePath).
+func WithServerRewrite(rewrite ServerRewrite) Option {
+	return func(cfg *uiConfig) {
+		cfg.serverRewrite = configValue[ServerRewrite]{Value: rewrite, IsSet: true}
+	}
+}
+
+// rewriteServers rewrites the servers of the JSON spec document for the request.
+func rewriteServers(r *http.Request, data []byte, rewrite ServerRewrite) ([]byte, error) {
+	scheme, host := requestOrigin(r, rewrite.TrustForwardedHeaders)
+
+	mapped, hasMapping := rewrite.Mapping[host]
+	if !hasMapping {
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			mapped, hasMapping = rewrite.Mapping[hostname]
+		}
+	}
+
+	if !hasMapping && !rewrite.UseRequestHost {
+		return data, nil
+	}
+
+	spec, err := parseSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case hasMapping && isSwagger2(spec):
+		setSwagger2Server(spec, mapped)
+	case hasMapping:
+		setServers(spec, mapped)
+	case isSwagger2(spec):
+		spec["host"] = host
+		spec["schemes"] = []any{scheme}
+	default:
+		forEachServerList(spec, func(servers []any) {
+			for _, server := range servers {
+				if serverObj, ok := server.(map[string]any); ok {
+					serverURL, _ := serverObj["url"].(string)
+					serverObj["url"] = replaceOrigin(serverURL, scheme, host)
+				}
+			}
+		})
+	}
+
+	return marshalSpec(spec)
+}
+
+// requestOrigin returns the scheme and host that the client used to send the request.
+func requestOrigin(r *http.Request, trustForwardedHeaders bool) (string, string) {
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	if !trustForwardedHeaders {
+		return scheme, host
+	}
+
+	if forwarded := r.Header.Get("Forwarded"); forwarded != "" {
+		// Only the first element was added by the proxy closest to the client.
+		first, _, _ := strings.Cut(forwarded, ",")
+		for _, pair := range strings.Split(first, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			value = strings.Trim(value, `"`)
+
+			switch strings.ToLower(key) {
+			case "proto":
+				scheme = value
+			case "host":
+				host = value
+			}
+		}
+
+		return scheme, host
+	}
+
+	if proto := firstHeaderValue(r, "X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	if forwardedHost := firstHeaderValue(r, "X-Forwarded-Host"); forwardedHost != "" {
+		host = forwardedHost
+	}
+
+	return scheme, host
+}
+
+func firstHeaderValue(r *http.Request, name string) string {
+	value, _, _ := strings.Cut(r.Header.Get(name), ",")
+	return strings.TrimSpace(value)
+}
+
+// setServers replaces all servers of an OpenAPI 3 spec by the given URLs. Servers
+// defined on path items and operations are removed.
+func setServers(spec map[string]any, urls []string) {
+	servers := make([]any, 0, len(urls))
+	for _, serverURL := range urls {
+		servers = append(servers, map[string]any{"url": serverURL})
+	}
+	spec["servers"] = servers
+
+	paths, _ := spec["paths"].(map[string]any)
+	for _, pathItem := range paths {
+		pathItemObj, ok := pathItem.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		delete(pathItemObj, "servers")
+		for _, method := range httpMethods {
+			if operation, ok := pathItemObj[method].(map[string]any); ok {
+				delete(operation, "servers")
+			}
+		}
+	}
+}
+
+// setSwagger2Server sets the host, basePath and schemes fields of a Swagger 2 spec from the
+// first URL. Swagger 2 does not support multiple hosts, so only the schemes of
+// other URLs are used.
+func setSwagger2Server(spec map[string]any, urls []string) {
+	var schemes []any
+	for idx, rawURL := range urls {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+
+		if idx == 0 {
+			spec["host"] = u.Host
+			spec["basePath"] = "/" + strings.TrimPrefix(u.Path, "/")
+		}
+
+		if u.Scheme != "" {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+
+	if len(schemes) > 0 {
+		spec["schemes"] = schemes
+	}
+}
+
+// forEachServerList calls fn for every servers list of an OpenAPI 3 spec, including
+// the servers of path items and operations.
+func forEachServerList(spec map[string]any, fn func(servers []any)) {
+	if servers, ok := spec["servers"].([]any); ok {
+		fn(servers)
+	}
+
+	paths, _ := spec["paths"].(map[string]any)
+	for _, pathItem := range paths {
+		pathItemObj, ok := pathItem.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if servers, ok := pathItemObj["servers"].([]any); ok {
+			fn(servers)
+		}
+
+		for _, method := range httpMethods {
+			if servers, ok := lookup(pathItemObj, method, "servers").([]any); ok {
+				fn(servers)
+			}
+		}
+	}
+}
+
+// replaceOrigin replaces scheme and host of an absolute server URL. Relative URLs are returned as they are.
+// Server URLs may contain variables (e.g., "https://{region}.example.com/v1"), so they cannot be parsed
+// using url.Parse.
+func replaceOrigin(serverURL, scheme, host string) string {
+	_, rest, ok := strings.Cut(serverURL, "://")
+	if !ok {
+		return serverURL
+	}
+
+	path := ""
+	if idx := strings.Index(rest, "/"); idx >= 0 {
+		path = rest[idx:]
+	}
+
+	return scheme + "://" + host + path
+}
@@ -0,0 +1,164 @@
+package go_swagger_ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+const serversTestSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1"},
+  "servers": [{"url": "https://api.example.com/v1"}, {"url": "/relative"}],
+  "paths": {"/pets": {"servers": [{"url": "https://{region}.example.com/v2"}], "get": {"responses": {"200": {"description": "ok"}}}}}
+}`
+
+func TestRewriteServers(t *testing.T) {
+	mapping := map[string][]string{
+		"docs.staging.example.com": {"https://api.staging.example.com/v1"},
+	}
+
+	tests := []struct {
+		name            string
+		rewrite         ServerRewrite
+		host            string
+		headers         map[string]string
+		expectedServers []string
+	}{
+		{
+			name:            "request host",
+			rewrite:         ServerRewrite{UseRequestHost: true},
+			host:            "docs.example.com:8080",
+			expectedServers: []string{"http://docs.example.com:8080/v1", "/relative", "http://docs.example.com:8080/v2"},
+		},
+		{
+			name:            "forwarded headers are ignored if not trusted",
+			rewrite:         ServerRewrite{UseRequestHost: true},
+			host:            "internal:8080",
+			headers:         map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "docs.example.com"},
+			expectedServers: []string{"http://internal:8080/v1", "/relative", "http://internal:8080/v2"},
+		},
+		{
+			name:            "trusted X-Forwarded headers",
+			rewrite:         ServerRewrite{UseRequestHost: true, TrustForwardedHeaders: true},
+			host:            "internal:8080",
+			headers:         map[string]string{"X-Forwarded-Proto": "https, http", "X-Forwarded-Host": "docs.example.com, proxy"},
+			expectedServers: []string{"https://docs.example.com/v1", "/relative", "https://docs.example.com/v2"},
+		},
+		{
+			name:            "trusted Forwarded header takes precedence",
+			rewrite:         ServerRewrite{UseRequestHost: true, TrustForwardedHeaders: true},
+			host:            "internal:8080",
+			headers:         map[string]string{"Forwarded": `proto=https;host="docs.example.com", host=proxy`, "X-Forwarded-Host": "other.example.com"},
+			expectedServers: []string{"https://docs.example.com/v1", "/relative", "https://docs.example.com/v2"},
+		},
+		{
+			name:            "mapping",
+			rewrite:         ServerRewrite{Mapping: mapping, UseRequestHost: true},
+			host:            "docs.staging.example.com:443",
+			expectedServers: []string{"https://api.staging.example.com/v1"},
+		},
+		{
+			name:            "mapping of untrusted forwarded host",
+			rewrite:         ServerRewrite{Mapping: mapping},
+			host:            "internal:8080",
+			headers:         map[string]string{"X-Forwarded-Host": "docs.staging.example.com"},
+			expectedServers: []string{"https://api.example.com/v1", "/relative", "https://{region}.example.com/v2"},
+		},
+		{
+			name:            "mapping of trusted forwarded host",
+			rewrite:         ServerRewrite{Mapping: mapping, TrustForwardedHeaders: true},
+			host:            "internal:8080",
+			headers:         map[string]string{"X-Forwarded-Host": "docs.staging.example.com"},
+			expectedServers: []string{"https://api.staging.example.com/v1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tt.host
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			data, err := rewriteServers(req, []byte(serversTestSpec), tt.rewrite)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spec, err := parseSpec(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var servers []string
+			forEachServerList(spec, func(list []any) {
+				for _, server := range list {
+					servers = append(servers, server.(map[string]any)["url"].(string))
+				}
+			})
+
+			if !slices.Equal(servers, tt.expectedServers) {
+				t.Errorf("expected servers %v, got %v", tt.expectedServers, servers)
+			}
+		})
+	}
+}
+
+func TestRewriteSwagger2Servers(t *testing.T) {
+	spec := `{"swagger": "2.0", "info": {"title": "Pets", "version": "1"}, "host": "api.example.com", "basePath": "/v1", "schemes": ["https"], "paths": {}}`
+
+	tests := []struct {
+		name             string
+		rewrite          ServerRewrite
+		expectedHost     string
+		expectedBasePath string
+		expectedSchemes  []string
+	}{
+		{
+			name:             "request host",
+			rewrite:          ServerRewrite{UseRequestHost: true, TrustForwardedHeaders: true},
+			expectedHost:     "docs.example.com",
+			expectedBasePath: "/v1",
+			expectedSchemes:  []string{"https"},
+		},
+		{
+			name:             "mapping",
+			rewrite:          ServerRewrite{Mapping: map[string][]string{"internal": {"http://api.internal/v2", "https://api.internal/v2"}}},
+			expectedHost:     "api.internal",
+			expectedBasePath: "/v2",
+			expectedSchemes:  []string{"http", "https"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = "internal"
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("X-Forwarded-Host", "docs.example.com")
+
+			data, err := rewriteServers(req, []byte(spec), tt.rewrite)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := parseSpec(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var schemes []string
+			list, _ := parsed["schemes"].([]any)
+			for _, scheme := range list {
+				schemes = append(schemes, scheme.(string))
+			}
+			if parsed["host"] != tt.expectedHost || parsed["basePath"] != tt.expectedBasePath || !slices.Equal(schemes, tt.expectedSchemes) {
+				t.Errorf("expected %s%s (%v), got %v%v (%v)", tt.expectedHost, tt.expectedBasePath, tt.expectedSchemes,
+					parsed["host"], parsed["basePath"], schemes)
+			}
+		})
+	}
+}
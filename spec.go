@@ -0,0 +1,413 @@
+package go_swagger_ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// httpMethods contains all operation keys of an OpenAPI path item object.
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// componentSections contains the paths of all sections of a spec that contain reusable
+// objects, which can be referenced using $ref (OpenAPI 3 and Swagger 2).
+var componentSections = [][]string{
+	{"components", "schemas"},
+	{"components", "responses"},
+	{"components", "parameters"},
+	{"components", "examples"},
+	{"components", "requestBodies"},
+	{"components", "headers"},
+	{"components", "links"},
+	{"components", "callbacks"},
+	{"components", "pathItems"},
+	{"definitions"},
+	{"parameters"},
+	{"responses"},
+}
+
+// Operation describes an API operation of a spec.
+type Operation struct {
+	// Path is the path template the operation is defined on (e.g., "/pets/{id}").
+	Path string
+	// Method is the lower case HTTP method of the operation (e.g., "get").
+	Method string
+	// OperationID is the operationId of the operation, if present.
+	OperationID string
+	// Tags is the list of tags of the operation.
+	Tags []string
+	// Extensions contains all vendor extensions (x-) of the operation.
+	// Extensions defined on the path item are included, unless the operation overrides them.
+	Extensions map[string]any
+}
+
+func parseSpec(data []byte) (map[string]any, error) {
+	spec := make(map[string]any)
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("cannot parse spec: %w", err)
+	}
+
+	return spec, nil
+}
+
+func marshalSpec(spec map[string]any) ([]byte, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal spec: %w", err)
+	}
+
+	return data, nil
+}
+
+// isSwagger2 reports whether the spec is a Swagger 2.0 document (as opposed to OpenAPI 3).
+func isSwagger2(spec map[string]any) bool {
+	_, ok := spec["swagger"]
+	return ok
+}
+
+// forEachOperation calls fn for every operation in the spec. If fn returns false,
+// the operation is removed from the spec. Path items without any remaining operations
+// are removed as well.
+func forEachOperation(spec map[string]any, fn func(op Operation, value map[string]any) bool) {
+	paths, _ := spec["paths"].(map[string]any)
+
+	for _, pathName := range sortedKeys(paths) {
+		pathItem, ok := paths[pathName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		hadOperations, removedAll := false, true
+		for _, method := range httpMethods {
+			value, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			hadOperations = true
+			if fn(newOperation(pathName, method, pathItem, value), value) {
+				removedAll = false
+			} else {
+				delete(pathItem, method)
+			}
+		}
+
+		if hadOperations && removedAll {
+			delete(paths, pathName)
+		}
+	}
+}
+
+func newOperation(path, method string, pathItem, value map[string]any) Operation {
+	op := Operation{
+		Path:       path,
+		Method:     method,
+		Extensions: make(map[string]any),
+	}
+
+	op.OperationID, _ = value["operationId"].(string)
+
+	tags, _ := value["tags"].([]any)
+	for _, tag := range tags {
+		if name, ok := tag.(string); ok {
+			op.Tags = append(op.Tags, name)
+		}
+	}
+
+	for _, obj := range []map[string]any{pathItem, value} {
+		for key, ext := range obj {
+			if strings.HasPrefix(key, "x-") {
+				op.Extensions[key] = ext
+			}
+		}
+	}
+
+	return op
+}
+
+// removeOperations removes all operations for which keep returns false. Tags and reusable
+// components (e.g., schemas) that are not used anymore after removing the operations are
+// removed as well. Tags and components that have not been used before are left untouched.
+func removeOperations(spec map[string]any, keep func(op Operation) bool) {
+	tagsBefore := usedTags(spec)
+	componentsBefore := referencedComponents(spec)
+
+	forEachOperation(spec, func(op Operation, _ map[string]any) bool {
+		return keep(op)
+	})
+
+	tagsAfter := usedTags(spec)
+	unusedTags := make(map[string]struct{})
+	for tag := range tagsBefore {
+		if _, ok := tagsAfter[tag]; !ok {
+			unusedTags[tag] = struct{}{}
+		}
+	}
+	removeTags(spec, unusedTags)
+
+	componentsAfter := referencedComponents(spec)
+	removeComponents(spec, func(ref string) bool {
+		_, before := componentsBefore[ref]
+		_, after := componentsAfter[ref]
+		return before && !after
+	})
+}
+
+// usedTags returns the names of all tags that are used by at least one operation.
+func usedTags(spec map[string]any) map[string]struct{} {
+	tags := make(map[string]struct{})
+
+	forEachOperation(spec, func(op Operation, _ map[string]any) bool {
+		for _, tag := range op.Tags {
+			tags[tag] = struct{}{}
+		}
+		return true
+	})
+
+	return tags
+}
+
+// removeTags removes the top-level tag definitions with the given names.
+func removeTags(spec map[string]any, names map[string]struct{}) {
+	tags, ok := spec["tags"].([]any)
+	if !ok {
+		return
+	}
+
+	remaining := make([]any, 0, len(tags))
+	for _, tag := range tags {
+		tagObj, _ := tag.(map[string]any)
+		name, _ := tagObj["name"].(string)
+		if _, remove := names[name]; !remove {
+			remaining = append(remaining, tag)
+		}
+	}
+
+	spec["tags"] = remaining
+}
+
+// pruneUnreferencedComponents removes all reusable objects (e.g., schemas in components.schemas
+// or definitions) that are not referenced from the remaining parts of the spec, either directly
+// or through other referenced components. Security schemes are never removed, because they
+// are referenced by name instead of $ref.
+func pruneUnreferencedComponents(spec map[string]any) {
+	referenced := referencedComponents(spec)
+
+	removeComponents(spec, func(ref string) bool {
+		_, ok := referenced[ref]
+		return !ok
+	})
+}
+
+// referencedComponents returns the references (e.g., "#/components/schemas/Pet") of all reusable
+// objects that are referenced from outside the component sections, either directly or
+// transitively through other referenced components.
+func referencedComponents(spec map[string]any) map[string]struct{} {
+	sections := componentSectionsOf(spec)
+
+	// Collect all references from outside the component sections first.
+	var pending []string
+	collectRefs(spec, func(ref string) { pending = append(pending, ref) }, func(path []string) bool {
+		for _, section := range componentSections {
+			if len(path) == len(section) && strings.Join(path, "/") == strings.Join(section, "/") {
+				return false
+			}
+		}
+		return true
+	})
+
+	// Follow references transitively through all referenced components.
+	referenced := make(map[string]struct{})
+	for len(pending) > 0 {
+		ref := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		sectionRef, name, ok := splitComponentRef(ref)
+		if !ok {
+			continue
+		}
+
+		key := sectionRef + "/" + escapeJSONPointer(name)
+		if _, seen := referenced[key]; seen {
+			continue
+		}
+		referenced[key] = struct{}{}
+
+		if component, ok := sections[sectionRef][name]; ok {
+			collectRefs(component, func(ref string) { pending = append(pending, ref) }, nil)
+		}
+	}
+
+	return referenced
+}
+
+// removeComponents removes all reusable objects for which remove returns true.
+// The function is called with the reference of the object (e.g., "#/components/schemas/Pet").
+func removeComponents(spec map[string]any, remove func(ref string) bool) {
+	for sectionRef, components := range componentSectionsOf(spec) {
+		for name := range components {
+			if remove(sectionRef + "/" + escapeJSONPointer(name)) {
+				delete(components, name)
+			}
+		}
+	}
+}
+
+// componentSectionsOf returns all component sections of the spec keyed by their
+// reference (e.g., "#/components/schemas").
+func componentSectionsOf(spec map[string]any) map[string]map[string]any {
+	sections := make(map[string]map[string]any)
+	for _, section := range componentSections {
+		if components, ok := lookup(spec, section...).(map[string]any); ok {
+			sections["#/"+strings.Join(section, "/")] = components
+		}
+	}
+
+	return sections
+}
+
+// splitComponentRef splits a local reference like "#/components/schemas/Pet" into the reference of
+// the section ("#/components/schemas") and the unescaped component name ("Pet").
+func splitComponentRef(ref string) (string, string, bool) {
+	for _, section := range componentSections {
+		prefix := "#/" + strings.Join(section, "/") + "/"
+		if rest, ok := strings.CutPrefix(ref, prefix); ok {
+			name, _, _ := strings.Cut(rest, "/")
+			return strings.TrimSuffix(prefix, "/"), unescapeJSONPointer(name), true
+		}
+	}
+
+	return "", "", false
+}
+
+// collectRefs calls fn for every $ref value found in v. If descend is not nil, it is called with
+// the path of every object key and the object is skipped if descend returns false.
+func collectRefs(v any, fn func(ref string), descend func(path []string) bool) {
+	var walk func(v any, path []string)
+	walk = func(v any, path []string) {
+		switch value := v.(type) {
+		case map[string]any:
+			if ref, ok := value["$ref"].(string); ok {
+				fn(ref)
+			}
+			for key, elem := range value {
+				elemPath := append(path[:len(path):len(path)], key)
+				if descend == nil || descend(elemPath) {
+					walk(elem, elemPath)
+				}
+			}
+		case []any:
+			for _, elem := range value {
+				walk(elem, path)
+			}
+		}
+	}
+
+	walk(v, nil)
+}
+
+// rewriteRefs replaces every $ref value found in v with the result of fn.
+func rewriteRefs(v any, fn func(ref string) string) {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, elem := range value {
+			if ref, ok := elem.(string); ok && key == "$ref" {
+				value[key] = fn(ref)
+				continue
+			}
+			rewriteRefs(elem, fn)
+		}
+	case []any:
+		for _, elem := range value {
+			rewriteRefs(elem, fn)
+		}
+	}
+}
+
+// resolveRef resolves a local reference (e.g., "#/components/schemas/Pet") within the spec.
+func resolveRef(spec map[string]any, ref string) (any, bool) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, false
+	}
+
+	var v any = spec
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+
+		switch value := v.(type) {
+		case map[string]any:
+			if v, ok = value[unescapeJSONPointer(token)]; !ok {
+				return nil, false
+			}
+		case []any:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(value) {
+				return nil, false
+			}
+			v = value[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// deref follows $ref values until an object without a reference is found. It returns
+// the object itself if it does not contain a reference or the reference cannot be resolved.
+func deref(spec map[string]any, v any) map[string]any {
+	obj, _ := v.(map[string]any)
+
+	// A limit protects against reference cycles.
+	for i := 0; i < 32; i++ {
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			break
+		}
+
+		resolved, ok := resolveRef(spec, ref)
+		if !ok {
+			break
+		}
+
+		obj, _ = resolved.(map[string]any)
+	}
+
+	return obj
+}
+
+// lookup returns the value at the given object path or nil if it does not exist.
+func lookup(v any, path ...string) any {
+	for _, key := range path {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = obj[key]
+	}
+
+	return v
+}
+
+func unescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
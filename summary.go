@@ -0,0 +1,348 @@
+package go_swagger_ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// SpecSummary gives an overview of the tags, operations, security requirements and servers of a spec.
+// See SummarizeSpec.
+type SpecSummary struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+	// Tags contains the tags in the order of the global tags list. Tags that are only used by
+	// operations follow in alphabetical order.
+	Tags       []TagSummary       `json:"tags"`
+	Operations []OperationSummary `json:"operations"`
+	// Security contains the global security requirements. See OperationSummary.Security.
+	Security        []string                `json:"security"`
+	SecuritySchemes []SecuritySchemeSummary `json:"securitySchemes"`
+	Servers         []ServerSummary         `json:"servers"`
+}
+
+// TagSummary describes a tag and the number of operations that use it.
+type TagSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Operations  int    `json:"operations"`
+}
+
+// OperationSummary describes an operation of a spec.
+type OperationSummary struct {
+	// Method is the upper case HTTP method of the operation (e.g., "GET").
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	OperationID string   `json:"operationId,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	// Security contains the alternative security requirements that apply to the operation, which are
+	// the global requirements, unless the operation overrides them. Schemes that are required together
+	// are joined by " + " (e.g., "apiKey + oauth2 (read, write)"). An empty list means that the
+	// operation does not require authentication.
+	Security []string `json:"security"`
+}
+
+// SecuritySchemeSummary describes a security scheme of a spec.
+type SecuritySchemeSummary struct {
+	Name string `json:"name"`
+	// Type describes the type of the scheme (e.g., "http (bearer)" or "apiKey (header X-API-Key)").
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// ServerSummary describes a server of a spec.
+type ServerSummary struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// SummarizeSpec returns a summary of a Swagger 2.0 or OpenAPI 3 spec in YAML or JSON format.
+// If tags are given, only operations with at least one of the tags are included.
+func SummarizeSpec(spec []byte, tags ...string) (*SpecSummary, error) {
+	jsonData, err := yamlOrJSONToJSON(spec)
+	if err == nil {
+		jsonData, err = convertSpec(jsonData)
+	}
+	var parsed map[string]any
+	if err == nil {
+		parsed, err = parseSpec(jsonData)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse spec: %w", err)
+	}
+
+	summary := &SpecSummary{
+		Tags:            []TagSummary{},
+		Operations:      []OperationSummary{},
+		Security:        securityRequirements(parsed["security"]),
+		SecuritySchemes: []SecuritySchemeSummary{},
+		Servers:         []ServerSummary{},
+	}
+	summary.Title, _ = lookup(parsed, "info", "title").(string)
+	summary.Version, _ = lookup(parsed, "info", "version").(string)
+
+	forEachOperation(parsed, func(op Operation, value map[string]any) bool {
+		if len(tags) > 0 && !containsAny(op.Tags, tags) {
+			return true
+		}
+
+		operation := OperationSummary{
+			Method:      strings.ToUpper(op.Method),
+			Path:        op.Path,
+			OperationID: op.OperationID,
+			Tags:        op.Tags,
+			Security:    summary.Security,
+		}
+		operation.Summary, _ = value["summary"].(string)
+		operation.Deprecated, _ = value["deprecated"].(bool)
+		if security, ok := value["security"]; ok {
+			operation.Security = securityRequirements(security)
+		}
+
+		summary.Operations = append(summary.Operations, operation)
+		return true
+	})
+
+	counts := make(map[string]int)
+	for _, op := range summary.Operations {
+		for _, tag := range op.Tags {
+			counts[tag]++
+		}
+	}
+
+	globalTags, _ := parsed["tags"].([]any)
+	for _, value := range globalTags {
+		tag, _ := value.(map[string]any)
+		name, _ := tag["name"].(string)
+		if _, ok := counts[name]; !ok && (name == "" || len(tags) > 0) {
+			continue
+		}
+
+		description, _ := tag["description"].(string)
+		summary.Tags = append(summary.Tags, TagSummary{Name: name, Description: description, Operations: counts[name]})
+		delete(counts, name)
+	}
+	for _, name := range sortedKeys(counts) {
+		summary.Tags = append(summary.Tags, TagSummary{Name: name, Operations: counts[name]})
+	}
+
+	schemes, _ := lookup(parsed, "components", "securitySchemes").(map[string]any)
+	for _, name := range sortedKeys(schemes) {
+		scheme := deref(parsed, schemes[name])
+		description, _ := scheme["description"].(string)
+		summary.SecuritySchemes = append(summary.SecuritySchemes, SecuritySchemeSummary{
+			Name:        name,
+			Type:        securitySchemeType(scheme),
+			Description: description,
+		})
+	}
+
+	servers, _ := parsed["servers"].([]any)
+	for _, value := range servers {
+		server, _ := value.(map[string]any)
+		if serverURL, ok := server["url"].(string); ok {
+			description, _ := server["description"].(string)
+			summary.Servers = append(summary.Servers, ServerSummary{URL: serverURL, Description: description})
+		}
+	}
+
+	return summary, nil
+}
+
+// securityRequirements returns the security requirements of a security field as strings.
+func securityRequirements(value any) []string {
+	requirements := []string{}
+
+	list, _ := value.([]any)
+	for _, item := range list {
+		requirement, _ := item.(map[string]any)
+
+		schemes := make([]string, 0, len(requirement))
+		for _, name := range sortedKeys(requirement) {
+			var scopes []string
+			values, _ := requirement[name].([]any)
+			for _, scope := range values {
+				if s, ok := scope.(string); ok {
+					scopes = append(scopes, s)
+				}
+			}
+
+			if len(scopes) > 0 {
+				name += " (" + strings.Join(scopes, ", ") + ")"
+			}
+			schemes = append(schemes, name)
+		}
+
+		// An empty requirement makes authentication optional.
+		if len(schemes) == 0 {
+			schemes = append(schemes, "none")
+		}
+		requirements = append(requirements, strings.Join(schemes, " + "))
+	}
+
+	return requirements
+}
+
+func securitySchemeType(scheme map[string]any) string {
+	schemeType, _ := scheme["type"].(string)
+
+	switch schemeType {
+	case "http":
+		if name, ok := scheme["scheme"].(string); ok {
+			return schemeType + " (" + strings.ToLower(name) + ")"
+		}
+	case "apiKey":
+		in, _ := scheme["in"].(string)
+		name, _ := scheme["name"].(string)
+		return strings.TrimSpace(fmt.Sprintf("%s (%s %s)", schemeType, in, name))
+	case "oauth2":
+		if flows, ok := scheme["flows"].(map[string]any); ok {
+			return schemeType + " (" + strings.Join(sortedKeys(flows), ", ") + ")"
+		}
+	}
+
+	return schemeType
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if slices.Contains(values, candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Table returns the summary as plain text tables with aligned columns.
+func (s *SpecSummary) Table() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s\n", s.Title, s.Version)
+
+	section := func(title string, header string, rows [][]string) {
+		fmt.Fprintf(&sb, "\n%s (%d)\n", title, len(rows))
+		if len(rows) == 0 {
+			return
+		}
+
+		var table strings.Builder
+		w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, header)
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		w.Flush()
+
+		// Empty cells in the last column are padded with spaces.
+		for _, line := range strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n") {
+			sb.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+	}
+
+	section("Tags", "NAME\tOPERATIONS\tDESCRIPTION", s.tagRows())
+	section("Operations", "METHOD\tPATH\tOPERATION ID\tSUMMARY\tTAGS\tSECURITY", s.operationRows())
+	section("Security schemes", "NAME\tTYPE\tDESCRIPTION", s.securitySchemeRows())
+	section("Servers", "URL\tDESCRIPTION", s.serverRows())
+
+	if len(s.Security) > 0 {
+		fmt.Fprintf(&sb, "\nGlobal security: %s\n", strings.Join(s.Security, " | "))
+	}
+
+	return sb.String()
+}
+
+// JSON returns the summary as JSON.
+func (s *SpecSummary) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Markdown returns the summary as Markdown tables.
+func (s *SpecSummary) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s %s\n", s.Title, s.Version)
+
+	section := func(title string, header []string, rows [][]string) {
+		if len(rows) == 0 {
+			return
+		}
+
+		fmt.Fprintf(&sb, "\n## %s\n\n| %s |\n|%s\n", title, strings.Join(header, " | "), strings.Repeat(" --- |", len(header)))
+		for _, row := range rows {
+			cells := make([]string, len(row))
+			for idx, cell := range row {
+				cells[idx] = strings.ReplaceAll(cell, "|", "\\|")
+			}
+			fmt.Fprintf(&sb, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+
+	section("Tags", []string{"Name", "Operations", "Description"}, s.tagRows())
+	if len(s.Operations) == 0 {
+		sb.WriteString("\nNo operations.\n")
+	}
+	section("Operations", []string{"Method", "Path", "Operation ID", "Summary", "Tags", "Security"}, s.operationRows())
+	section("Security schemes", []string{"Name", "Type", "Description"}, s.securitySchemeRows())
+	section("Servers", []string{"URL", "Description"}, s.serverRows())
+
+	if len(s.Security) > 0 {
+		fmt.Fprintf(&sb, "\nGlobal security: %s\n", strings.Join(s.Security, " | "))
+	}
+
+	return sb.String()
+}
+
+func (s *SpecSummary) tagRows() [][]string {
+	rows := make([][]string, len(s.Tags))
+	for idx, tag := range s.Tags {
+		rows[idx] = []string{tag.Name, fmt.Sprint(tag.Operations), firstLine(tag.Description)}
+	}
+
+	return rows
+}
+
+func (s *SpecSummary) operationRows() [][]string {
+	rows := make([][]string, len(s.Operations))
+	for idx, op := range s.Operations {
+		summary := firstLine(op.Summary)
+		if op.Deprecated {
+			summary = strings.TrimSpace("(deprecated) " + summary)
+		}
+
+		security := "-"
+		if len(op.Security) > 0 {
+			security = strings.Join(op.Security, " | ")
+		}
+
+		rows[idx] = []string{op.Method, op.Path, op.OperationID, summary, strings.Join(op.Tags, ", "), security}
+	}
+
+	return rows
+}
+
+func (s *SpecSummary) securitySchemeRows() [][]string {
+	rows := make([][]string, len(s.SecuritySchemes))
+	for idx, scheme := range s.SecuritySchemes {
+		rows[idx] = []string{scheme.Name, scheme.Type, firstLine(scheme.Description)}
+	}
+
+	return rows
+}
+
+func (s *SpecSummary) serverRows() [][]string {
+	rows := make([][]string, len(s.Servers))
+	for idx, server := range s.Servers {
+		rows[idx] = []string{server.URL, firstLine(server.Description)}
+	}
+
+	return rows
+}
+
+// firstLine returns the first line of a (Markdown) description.
+func firstLine(text string) string {
+	text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,183 @@
+package go_swagger_ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const summaryTestSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+servers:
+  - {url: "https://api.example.com", description: Production}
+security:
+  - apiKey: []
+tags:
+  - {name: users, description: "Users\nof the API"}
+  - {name: pets, description: Pets}
+  - {name: unused}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      tags: [pets]
+    post:
+      operationId: createPet
+      tags: [pets, admin]
+      security:
+        - {apiKey: [], oauth: [write]}
+        - {}
+  /users:
+    get:
+      operationId: listUsers
+      tags: [users]
+      deprecated: true
+      security: []
+  /health:
+    get: {operationId: health}
+components:
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+    oauth:
+      type: oauth2
+      description: OAuth
+      flows: {clientCredentials: {tokenUrl: /token, scopes: {write: Write}}}
+`
+
+func TestSummarizeSpec(t *testing.T) {
+	summary, err := SummarizeSpec([]byte(summaryTestSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &SpecSummary{
+		Title:   "Pets",
+		Version: "1.0.0",
+		Tags: []TagSummary{
+			{Name: "users", Description: "Users\nof the API", Operations: 1},
+			{Name: "pets", Description: "Pets", Operations: 2},
+			{Name: "unused"},
+			{Name: "admin", Operations: 1},
+		},
+		Operations: []OperationSummary{
+			{Method: "GET", Path: "/health", OperationID: "health", Security: []string{"apiKey"}},
+			{Method: "GET", Path: "/pets", OperationID: "listPets", Summary: "List pets", Tags: []string{"pets"}, Security: []string{"apiKey"}},
+			{Method: "POST", Path: "/pets", OperationID: "createPet", Tags: []string{"pets", "admin"}, Security: []string{"apiKey + oauth (write)", "none"}},
+			{Method: "GET", Path: "/users", OperationID: "listUsers", Tags: []string{"users"}, Deprecated: true, Security: []string{}},
+		},
+		Security: []string{"apiKey"},
+		SecuritySchemes: []SecuritySchemeSummary{
+			{Name: "apiKey", Type: "apiKey (header X-API-Key)"},
+			{Name: "oauth", Type: "oauth2 (clientCredentials)", Description: "OAuth"},
+		},
+		Servers: []ServerSummary{{URL: "https://api.example.com", Description: "Production"}},
+	}
+
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected summary\n%+v\ngot\n%+v", expected, summary)
+	}
+}
+
+func TestSummarizeSpecFiltersTags(t *testing.T) {
+	tests := []struct {
+		name               string
+		tags               []string
+		expectedTags       []TagSummary
+		expectedOperations []string
+	}{
+		{
+			name:               "single tag",
+			tags:               []string{"pets"},
+			expectedTags:       []TagSummary{{Name: "pets", Description: "Pets", Operations: 2}, {Name: "admin", Operations: 1}},
+			expectedOperations: []string{"listPets", "createPet"},
+		},
+		{
+			name:               "tag that is not in the global tags list",
+			tags:               []string{"admin"},
+			expectedTags:       []TagSummary{{Name: "pets", Description: "Pets", Operations: 1}, {Name: "admin", Operations: 1}},
+			expectedOperations: []string{"createPet"},
+		},
+		{
+			name:               "multiple tags",
+			tags:               []string{"users", "admin"},
+			expectedTags:       []TagSummary{{Name: "users", Description: "Users\nof the API", Operations: 1}, {Name: "pets", Description: "Pets", Operations: 1}, {Name: "admin", Operations: 1}},
+			expectedOperations: []string{"createPet", "listUsers"},
+		},
+		{
+			name:               "unused tag",
+			tags:               []string{"unused"},
+			expectedTags:       []TagSummary{},
+			expectedOperations: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := SummarizeSpec([]byte(summaryTestSpec), tt.tags...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(summary.Tags, tt.expectedTags) {
+				t.Errorf("expected tags %+v, got %+v", tt.expectedTags, summary.Tags)
+			}
+
+			operations := []string{}
+			for _, op := range summary.Operations {
+				operations = append(operations, op.OperationID)
+			}
+			if !reflect.DeepEqual(operations, tt.expectedOperations) {
+				t.Errorf("expected operations %v, got %v", tt.expectedOperations, operations)
+			}
+		})
+	}
+}
+
+func TestSpecSummaryFormats(t *testing.T) {
+	summary, err := SummarizeSpec([]byte(summaryTestSpec), "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTable := `Pets 1.0.0
+
+Tags (1)
+NAME   OPERATIONS  DESCRIPTION
+users  1           Users
+
+Operations (1)
+METHOD  PATH    OPERATION ID  SUMMARY       TAGS   SECURITY
+GET     /users  listUsers     (deprecated)  users  -
+
+Security schemes (2)
+NAME    TYPE                        DESCRIPTION
+apiKey  apiKey (header X-API-Key)
+oauth   oauth2 (clientCredentials)  OAuth
+
+Servers (1)
+URL                      DESCRIPTION
+https://api.example.com  Production
+
+Global security: apiKey
+`
+	if table := summary.Table(); table != expectedTable {
+		t.Errorf("expected table\n%s\ngot\n%s", expectedTable, table)
+	}
+
+	markdown := summary.Markdown()
+	for _, expected := range []string{
+		"# Pets 1.0.0\n",
+		"| Name | Operations | Description |\n| --- | --- | --- |\n| users | 1 | Users |\n",
+		"| GET | /users | listUsers | (deprecated) | users | - |\n",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("expected Markdown to contain %q, got\n%s", expected, markdown)
+		}
+	}
+
+	if _, err := SummarizeSpec([]byte("{")); err == nil {
+		t.Error("expected an error for an invalid spec")
+	}
+}
@@ -1,4 +1,4 @@
-window.onload = function() {
+window.addEventListener("load", function() {
   //<editor-fold desc="Changeable Configuration Block">
 
   // the following lines will be replaced by docker/configurator, when it runs in a docker-container
@@ -36,10 +36,47 @@ window.onload = function() {
     maxDisplayedTags: blankToUndefinedNumber('{{ .MaxDisplayedTags }}'),
     urls: blankToUndefinedObject('{{ .URLs }}'),
     "urls.primaryName": blankToUndefined('{{ .PrimaryURL }}'),
+    requestInterceptor: proxyRequestInterceptor(blankToUndefined('{{ .ProxyURL }}')),
   });
+
+  const oauth2 = blankToUndefinedObject('{{ .OAuth2 }}')
+  if (oauth2) {
+    window.ui.initOAuth(oauth2)
+  }
   //</editor-fold>
-};
+});
+
+
+function proxyRequestInterceptor(proxyURL) {
+  if (!proxyURL) {
+    return undefined
+  }
 
+  return (req) => {
+    // Requests that load the spec and requests to the origin Swagger UI is served from are not proxied.
+    const target = new URL(req.url, document.baseURI)
+    if (req.loadSpec || target.origin === window.location.origin) {
+      return req
+    }
+
+    req.url = new URL(proxyURL, document.baseURI).href + '?url=' + encodeURIComponent(target.href)
+    req.headers = req.headers || {}
+
+    // The proxy refuses requests without this header, which other sites cannot send.
+    req.headers['X-Try-It-Out-Proxy'] = 'true'
+
+    // The Authorization header of the proxy request belongs to the origin Swagger UI is served from
+    // (see WithAuthorizer), so the header for the API is sent separately and restored by the proxy.
+    for (const name of Object.keys(req.headers || {})) {
+      if (name.toLowerCase() === 'authorization') {
+        req.headers['X-Try-It-Out-Authorization'] = req.headers[name]
+        delete req.headers[name]
+      }
+    }
+
+    return req
+  }
+}
 
 function blankToUndefined(input) {
   return (input || '').trim() === '' ? undefined : input
@@ -0,0 +1,266 @@
+package go_swagger_ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SpecTransform modifies a parsed spec in place. The spec is the result of unmarshalling the
+// JSON representation of the spec document into a map. See WithSpecTransforms.
+type SpecTransform func(spec map[string]any) error
+
+// WithSpecTransforms sets a list of transformations that are applied to the spec in the given order.
+// Transformations are applied once when the handler is created (or every time the spec file is
+// reloaded when WithSpecFilePath is used), so the transformed parts of the spec never reach the browser.
+// This option only has an effect on specs that are embedded into Swagger UI (see WithSpec and WithSpecFilePath).
+// See RemoveMarked, RemoveExtensions and PruneUnreferencedComponents for ready-made transformations.
+func WithSpecTransforms(transforms ...SpecTransform) Option {
+	return func(cfg *uiConfig) {
+		cfg.specTransforms = append(cfg.specTransforms, transforms...)
+	}
+}
+
+// RemoveMarked returns a SpecTransform that removes all parts of the spec that are marked with the given
+// vendor extension set to true (e.g., "x-internal: true"). This includes path items, operations, parameters,
+// reusable components (e.g., schemas), schema properties and tags. Tags and components that are not used
+// anymore after removing operations are removed as well. Make sure that removed components are not
+// referenced from the remaining parts of the spec.
+func RemoveMarked(extension string) SpecTransform {
+	return func(spec map[string]any) error {
+		marked := func(v any) bool {
+			obj, _ := v.(map[string]any)
+			value, _ := obj[extension].(bool)
+			return value
+		}
+
+		paths, _ := spec["paths"].(map[string]any)
+		removeOperations(spec, func(op Operation) bool {
+			pathItem, _ := paths[op.Path].(map[string]any)
+			return !marked(pathItem) && !marked(pathItem[op.Method])
+		})
+
+		// Path items without operations (e.g., containing only a $ref) are not covered above.
+		for name, pathItem := range paths {
+			if marked(pathItem) {
+				delete(paths, name)
+			}
+		}
+
+		for _, components := range componentSectionsOf(spec) {
+			for name, component := range components {
+				if marked(component) {
+					delete(components, name)
+				}
+			}
+		}
+
+		if tags, ok := spec["tags"].([]any); ok {
+			spec["tags"] = removeMarkedElements(tags, marked)
+		}
+
+		walkObjects(spec, func(obj map[string]any) {
+			if parameters, ok := obj["parameters"].([]any); ok {
+				obj["parameters"] = removeMarkedElements(parameters, marked)
+			}
+
+			properties, ok := obj["properties"].(map[string]any)
+			if !ok {
+				return
+			}
+
+			for name, property := range properties {
+				if !marked(property) {
+					continue
+				}
+
+				delete(properties, name)
+				if required, ok := obj["required"].([]any); ok {
+					obj["required"] = removeMarkedElements(required, func(v any) bool { return v == name })
+				}
+			}
+		})
+
+		return nil
+	}
+}
+
+// RemoveExtensions returns a SpecTransform that removes all vendor extensions whose name starts with
+// one of the given prefixes (e.g., "x-internal-" or "x-amazon-"). Pass "x-" to remove all extensions.
+// Names of schema properties, headers, components, etc. that happen to start with a prefix are not removed.
+func RemoveExtensions(prefixes ...string) SpecTransform {
+	return func(spec map[string]any) error {
+		for _, prefix := range prefixes {
+			if !strings.HasPrefix(prefix, "x-") {
+				return fmt.Errorf("extension prefix %q does not start with \"x-\"", prefix)
+			}
+		}
+
+		removeExtensions(spec, nil, prefixes)
+
+		return nil
+	}
+}
+
+// PruneUnreferencedComponents returns a SpecTransform that removes all reusable components (e.g., schemas,
+// parameters, responses) that are not referenced from the remaining parts of the spec. This is useful after
+// removing operations with RemoveMarked. Security schemes are never removed.
+func PruneUnreferencedComponents() SpecTransform {
+	return func(spec map[string]any) error {
+		pruneUnreferencedComponents(spec)
+		return nil
+	}
+}
+
+// namedMapKeys contains keys of objects whose values are maps from names to objects
+// (as opposed to objects with fixed fields and extensions).
+var namedMapKeys = map[string]struct{}{
+	"properties":          {},
+	"patternProperties":   {},
+	"headers":             {},
+	"definitions":         {},
+	"securityDefinitions": {},
+	"examples":            {},
+	"encoding":            {},
+	"content":             {},
+	"mapping":             {},
+	"variables":           {},
+	"scopes":              {},
+	"links":               {},
+	"$defs":               {},
+	"dependentSchemas":    {},
+}
+
+// literalKeys contains keys of objects whose values are user defined data (e.g., examples)
+// that must not be modified.
+var literalKeys = map[string]struct{}{
+	"example": {},
+	"enum":    {},
+	"const":   {},
+	"value":   {},
+}
+
+// removeExtensions removes vendor extensions from v, which is located at the given path in the spec.
+func removeExtensions(v any, path []string, prefixes []string) {
+	switch value := v.(type) {
+	case map[string]any:
+		named := isNamedMap(path)
+
+		for key, elem := range value {
+			if !named && hasAnyPrefix(key, prefixes) {
+				delete(value, key)
+				continue
+			}
+
+			if !named && isLiteral(key, path) {
+				continue
+			}
+
+			removeExtensions(elem, append(path[:len(path):len(path)], key), prefixes)
+		}
+	case []any:
+		parent := ""
+		if len(path) > 0 {
+			parent = path[len(path)-1]
+		}
+
+		for _, elem := range value {
+			// Security requirements are maps from security scheme names to scopes.
+			if parent == "security" {
+				continue
+			}
+			// Array elements are always objects with fixed fields, never named maps.
+			removeExtensions(elem, append(path[:len(path):len(path)], "[]"), prefixes)
+		}
+	}
+}
+
+// isNamedMap reports whether the map at the given path maps names to objects.
+func isNamedMap(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	// Values of named maps are always regular objects, so a named map can never
+	// be directly contained in another named map.
+	if len(path) > 1 && isNamedMap(path[:len(path)-1]) {
+		return false
+	}
+
+	parent := path[len(path)-1]
+
+	switch {
+	case len(path) == 2 && path[0] == "components":
+		return true
+	case len(path) == 1 && (parent == "parameters" || parent == "responses"):
+		return true // Swagger 2 reusable parameters and responses
+	}
+
+	_, ok := namedMapKeys[parent]
+	return ok
+}
+
+func isLiteral(key string, path []string) bool {
+	if key == "default" {
+		// "default" is a response if it is contained in a responses object.
+		return len(path) == 0 || path[len(path)-1] != "responses"
+	}
+
+	_, ok := literalKeys[key]
+	return ok
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// walkObjects calls fn for every object (map) in v, including v itself.
+func walkObjects(v any, fn func(obj map[string]any)) {
+	switch value := v.(type) {
+	case map[string]any:
+		fn(value)
+		for _, elem := range value {
+			walkObjects(elem, fn)
+		}
+	case []any:
+		for _, elem := range value {
+			walkObjects(elem, fn)
+		}
+	}
+}
+
+func removeMarkedElements(values []any, marked func(v any) bool) []any {
+	remaining := make([]any, 0, len(values))
+	for _, value := range values {
+		if !marked(value) {
+			remaining = append(remaining, value)
+		}
+	}
+
+	return remaining
+}
+
+// transformSpec applies all transformations to the JSON spec document.
+func transformSpec(data []byte, transforms []SpecTransform) ([]byte, error) {
+	if len(transforms) == 0 {
+		return data, nil
+	}
+
+	spec, err := parseSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transform := range transforms {
+		if err := transform(spec); err != nil {
+			return nil, fmt.Errorf("cannot transform spec: %w", err)
+		}
+	}
+
+	return marshalSpec(spec)
+}
@@ -0,0 +1,281 @@
+package go_swagger_ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func parseTestSpec(t *testing.T, spec string) map[string]any {
+	t.Helper()
+
+	data, err := yamlOrJSONToJSON([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := parseSpec(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
+
+func TestSpecTransforms(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform SpecTransform
+		spec      string
+		expected  string
+	}{
+		{
+			name:      "remove marked operations and their tags and components",
+			transform: RemoveMarked("x-internal"),
+			spec: `
+openapi: 3.0.3
+tags: [{name: pets}, {name: admin}]
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      responses: {"200": {$ref: "#/components/responses/Pets"}}
+    delete:
+      x-internal: true
+      tags: [admin]
+      responses: {"204": {$ref: "#/components/responses/Deleted"}}
+  /admin:
+    x-internal: true
+    get: {tags: [admin], responses: {}}
+  /internal:
+    x-internal: true
+    $ref: "other.yaml#/paths/~1internal"
+components:
+  responses:
+    Pets: {description: ok}
+    Deleted: {description: deleted}
+    Unused: {description: unused}
+`,
+			expected: `
+openapi: 3.0.3
+tags: [{name: pets}]
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      responses: {"200": {$ref: "#/components/responses/Pets"}}
+components:
+  responses:
+    Pets: {description: ok}
+    Unused: {description: unused}
+`,
+		},
+		{
+			name:      "remove marked parameters, properties, components and tags",
+			transform: RemoveMarked("x-internal"),
+			spec: `
+openapi: 3.0.3
+tags: [{name: pets}, {name: debug, x-internal: true}]
+paths:
+  /pets:
+    parameters: [{name: trace, in: header, x-internal: true}]
+    get:
+      tags: [pets]
+      parameters: [{name: limit, in: query}, {name: debug, in: query, x-internal: true}]
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name, secret]
+      properties:
+        name: {type: string}
+        secret: {type: string, x-internal: true}
+    Debug: {type: object, x-internal: true}
+`,
+			expected: `
+openapi: 3.0.3
+tags: [{name: pets}]
+paths:
+  /pets:
+    parameters: []
+    get:
+      tags: [pets]
+      parameters: [{name: limit, in: query}]
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+`,
+		},
+		{
+			name:      "markers set to false are kept",
+			transform: RemoveMarked("x-internal"),
+			spec: `
+openapi: 3.0.3
+paths:
+  /pets: {get: {x-internal: false}, post: {x-internal: "true"}}
+`,
+			expected: `
+openapi: 3.0.3
+paths:
+  /pets: {get: {x-internal: false}, post: {x-internal: "true"}}
+`,
+		},
+		{
+			name:      "remove extensions",
+			transform: RemoveExtensions("x-internal-", "x-amazon-"),
+			spec: `
+openapi: 3.0.3
+x-internal-owner: team
+x-logo: logo.png
+paths:
+  /pets:
+    x-amazon-apigateway-any-method: {}
+    get:
+      x-internal-note: note
+      security: [{x-internal-key: []}]
+      responses:
+        default: {description: error, x-internal-note: note}
+components:
+  schemas:
+    x-internal-name:
+      type: object
+      properties:
+        x-amazon-id: {type: string, x-amazon-format: id}
+      example: {x-internal-value: 1}
+      enum: [{x-internal-value: 1}]
+      default: {x-internal-value: 1}
+`,
+			expected: `
+openapi: 3.0.3
+x-logo: logo.png
+paths:
+  /pets:
+    get:
+      security: [{x-internal-key: []}]
+      responses:
+        default: {description: error}
+components:
+  schemas:
+    x-internal-name:
+      type: object
+      properties:
+        x-amazon-id: {type: string}
+      example: {x-internal-value: 1}
+      enum: [{x-internal-value: 1}]
+      default: {x-internal-value: 1}
+`,
+		},
+		{
+			name:      "remove extensions of a Swagger 2 spec",
+			transform: RemoveExtensions("x-"),
+			spec: `
+swagger: "2.0"
+x-logo: logo.png
+parameters:
+  x-limit: {name: limit, in: query, type: integer, x-example: 10}
+responses:
+  x-error: {description: error}
+definitions:
+  x-pet: {type: object, x-nullable: true}
+`,
+			expected: `
+swagger: "2.0"
+parameters:
+  x-limit: {name: limit, in: query, type: integer}
+responses:
+  x-error: {description: error}
+definitions:
+  x-pet: {type: object}
+`,
+		},
+		{
+			name:      "prune unreferenced components",
+			transform: PruneUnreferencedComponents(),
+			spec: `
+openapi: 3.0.3
+paths:
+  /pets:
+    get:
+      responses: {"200": {$ref: "#/components/responses/Pets"}}
+components:
+  responses:
+    Pets:
+      description: ok
+      content: {application/json: {schema: {$ref: "#/components/schemas/Pets"}}}
+  schemas:
+    Pets: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+    Pet: {type: object}
+    Owner: {type: object, properties: {pets: {$ref: "#/components/schemas/Pets"}}}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+`,
+			expected: `
+openapi: 3.0.3
+paths:
+  /pets:
+    get:
+      responses: {"200": {$ref: "#/components/responses/Pets"}}
+components:
+  responses:
+    Pets:
+      description: ok
+      content: {application/json: {schema: {$ref: "#/components/schemas/Pets"}}}
+  schemas:
+    Pets: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+    Pet: {type: object}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := parseTestSpec(t, tt.spec)
+			if err := tt.transform(spec); err != nil {
+				t.Fatal(err)
+			}
+
+			if expected := parseTestSpec(t, tt.expected); !reflect.DeepEqual(spec, expected) {
+				t.Errorf("expected spec\n%v\ngot\n%v", expected, spec)
+			}
+		})
+	}
+}
+
+func TestRemoveExtensionsRejectsInvalidPrefixes(t *testing.T) {
+	spec := parseTestSpec(t, `{"openapi": "3.0.3", "x-logo": "logo.png"}`)
+	if err := RemoveExtensions("x-", "internal-")(spec); err == nil {
+		t.Error("expected an error for a prefix without \"x-\"")
+	}
+}
+
+func TestTransformSpecAppliesTransformsInOrder(t *testing.T) {
+	var calls []string
+	transform := func(name string) SpecTransform {
+		return func(spec map[string]any) error {
+			calls = append(calls, name)
+			spec["x-last"] = name
+			return nil
+		}
+	}
+
+	data, err := transformSpec([]byte(`{"openapi": "3.0.3"}`), []SpecTransform{transform("first"), transform("second")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"first", "second"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+	if spec := parseTestSpec(t, string(data)); spec["x-last"] != "second" {
+		t.Errorf("expected the result of the last transform, got %v", spec)
+	}
+
+	if _, err := transformSpec([]byte(`{}`), []SpecTransform{RemoveExtensions("internal")}); err == nil {
+		t.Error("expected an error of the transform")
+	}
+}
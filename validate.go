@@ -0,0 +1,651 @@
+package go_swagger_ui
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity is the severity of a Diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic describes a problem found in a spec.
+type Diagnostic struct {
+	// Severity is the severity of the problem.
+	Severity Severity `json:"severity"`
+	// Rule is the name of the rule that found the problem (e.g., "unique-operation-id").
+	Rule string `json:"rule"`
+	// Message describes the problem.
+	Message string `json:"message"`
+	// Pointer is the JSON pointer of the location of the problem in the spec (e.g., "/paths/~1pets/get").
+	Pointer string `json:"pointer"`
+	// Line is the 1-based line number of the location of the problem in the spec document.
+	// It is 0 if the spec document was generated (see WithSpecValidation).
+	Line int `json:"line"`
+	// Column is the 1-based column number of the location of the problem in the spec document.
+	// It is 0 if the spec document was generated (see WithSpecValidation).
+	Column int `json:"column"`
+}
+
+// String returns a human-readable representation of the diagnostic.
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s %s: %s (%s)", d.Severity, d.Rule, d.Message, d.Pointer)
+	}
+
+	return fmt.Sprintf("%d:%d %s %s: %s (%s)", d.Line, d.Column, d.Severity, d.Rule, d.Message, d.Pointer)
+}
+
+// ValidationError is returned if a spec contains errors.
+type ValidationError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *ValidationError) Error() string {
+	var errs []string
+	for _, d := range e.Diagnostics {
+		if d.Severity == SeverityError {
+			errs = append(errs, d.String())
+		}
+	}
+
+	return fmt.Sprintf("spec is invalid (%d errors): %s", len(errs), strings.Join(errs, "; "))
+}
+
+// WithSpecValidation validates the spec (see ValidateSpec) when the handler is created. If the spec contains
+// errors, NewHandler panics. Warnings are logged. When WithSpecFilePath is used, the spec is validated every
+// time it is reloaded and errors are logged and returned with status code 500 instead.
+// Bundled specs (see WithSpecFS and WithSpecFilePath) and merged specs (see WithMergedSpecs) are
+// validated after they have been built, so their diagnostics do not contain lines and columns.
+func WithSpecValidation() Option {
+	return func(cfg *uiConfig) {
+		cfg.specValidation = true
+	}
+}
+
+// ValidateSpec validates a Swagger 2.0, OpenAPI 3.0 or OpenAPI 3.1 document (as YAML or JSON).
+// It is not a validation against the JSON Schemas of the specifications. Only the spec version,
+// the info object, paths, operations, responses, parameters and servers are checked for required
+// fields, unknown fields and allowed values, along with semantic rules, such as unique operation IDs,
+// resolvable references, declared security schemes and consistent path parameters. Schema objects are
+// only checked for undefined required properties and security scheme definitions are not validated.
+// All diagnostics contain the line and column of the problem in the document. An error is only
+// returned if the document cannot be parsed at all.
+func ValidateSpec(spec []byte) ([]Diagnostic, error) {
+	diagnostics, err := validateSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return withPositions(spec, diagnostics), nil
+}
+
+// validateSpec is like ValidateSpec but does not determine the lines and columns of the diagnostics.
+func validateSpec(spec []byte) ([]Diagnostic, error) {
+	jsonSpec, err := yamlOrJSONToJSON(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseSpec(jsonSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &validator{spec: doc}
+	v.validate()
+
+	return v.diagnostics, nil
+}
+
+// checkDiagnostics returns a *ValidationError if diagnostics contain errors and logs all warnings.
+func checkDiagnostics(diagnostics []Diagnostic) error {
+	var hasErrors bool
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			hasErrors = true
+		} else if d.Severity == SeverityWarning {
+			slog.Warn("spec validation warning", "diagnostic", d.String())
+		}
+	}
+
+	if hasErrors {
+		return &ValidationError{Diagnostics: diagnostics}
+	}
+
+	return nil
+}
+
+// withPositions sets line and column of all diagnostics by looking up their JSON pointers in the
+// YAML node tree of the document. If a pointer cannot be found, the position of the closest
+// parent is used. Diagnostics are sorted by position.
+func withPositions(spec []byte, diagnostics []Diagnostic) []Diagnostic {
+	positions := make(map[string][2]int)
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(spec, &root); err == nil {
+		indexPositions(&root, "", positions)
+	}
+
+	for idx := range diagnostics {
+		pointer := diagnostics[idx].Pointer
+		for {
+			if pos, ok := positions[pointer]; ok {
+				diagnostics[idx].Line, diagnostics[idx].Column = pos[0], pos[1]
+				break
+			}
+
+			slash := strings.LastIndex(pointer, "/")
+			if slash < 0 {
+				break
+			}
+			pointer = pointer[:slash]
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+
+	return diagnostics
+}
+
+func indexPositions(node *yaml.Node, pointer string, positions map[string][2]int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			indexPositions(child, pointer, positions)
+		}
+		return
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			indexPositions(node.Alias, pointer, positions)
+		}
+		return
+	}
+
+	if _, ok := positions[pointer]; !ok {
+		positions[pointer] = [2]int{node.Line, node.Column}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPointer := pointer + "/" + escapeJSONPointer(key.Value)
+
+			// Point to the key, because it is usually more helpful than the start of the value.
+			positions[childPointer] = [2]int{key.Line, key.Column}
+			indexPositions(value, childPointer, positions)
+		}
+	case yaml.SequenceNode:
+		for idx, child := range node.Content {
+			indexPositions(child, pointer+"/"+strconv.Itoa(idx), positions)
+		}
+	}
+}
+
+type validator struct {
+	spec        map[string]any
+	diagnostics []Diagnostic
+}
+
+func (v *validator) report(severity Severity, rule, pointer, format string, args ...any) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Severity: severity,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+		Pointer:  pointer,
+	})
+}
+
+var (
+	openAPI30VersionPattern = regexp.MustCompile(`^3\.0\.\d+(-.+)?$`)
+	openAPI31VersionPattern = regexp.MustCompile(`^3\.1\.\d+(-.+)?$`)
+)
+
+// specVersion returns "2.0", "3.0" or "3.1" or an empty string if the version is not supported.
+func (v *validator) specVersion() string {
+	if version, ok := v.spec["swagger"]; ok {
+		if fmt.Sprint(version) == "2.0" {
+			return "2.0"
+		}
+		v.report(SeverityError, "spec-version", "/swagger", "unsupported Swagger version %q, expected \"2.0\"", version)
+		return ""
+	}
+
+	version, ok := v.spec["openapi"].(string)
+	if !ok {
+		v.report(SeverityError, "spec-version", "", "missing \"openapi\" or \"swagger\" field")
+		return ""
+	}
+
+	switch {
+	case openAPI30VersionPattern.MatchString(version):
+		return "3.0"
+	case openAPI31VersionPattern.MatchString(version):
+		return "3.1"
+	}
+
+	v.report(SeverityError, "spec-version", "/openapi", "unsupported OpenAPI version %q", version)
+	return ""
+}
+
+func (v *validator) validate() {
+	version := v.specVersion()
+	if version == "" {
+		return
+	}
+
+	v.validateInfo()
+	v.validatePaths(version)
+	v.validateComponentNames()
+	v.validateSecurityRequirements(version)
+	v.validateRefs()
+	v.validateSchemas()
+
+	if version != "2.0" {
+		v.validateServers("/servers", v.spec["servers"])
+	}
+}
+
+func (v *validator) validateInfo() {
+	info, ok := v.spec["info"].(map[string]any)
+	if !ok {
+		v.report(SeverityError, "required-field", "", "missing required field \"info\"")
+		return
+	}
+
+	for _, field := range []string{"title", "version"} {
+		if _, ok := info[field].(string); !ok {
+			v.report(SeverityError, "required-field", "/info", "missing required string field \"info.%s\"", field)
+		}
+	}
+}
+
+var pathItemFields = toSet(append([]string{"$ref", "summary", "description", "servers", "parameters"}, httpMethods...))
+
+var operationFields = toSet([]string{
+	"tags", "summary", "description", "externalDocs", "operationId", "parameters", "requestBody", "responses",
+	"callbacks", "deprecated", "security", "servers", "consumes", "produces", "schemes",
+})
+
+var responseCodePattern = regexp.MustCompile(`^([1-5][0-9]{2}|[1-5]XX|default)$`)
+
+func (v *validator) validatePaths(version string) {
+	paths, ok := v.spec["paths"].(map[string]any)
+	if !ok {
+		_, hasPaths := v.spec["paths"]
+		_, hasComponents := v.spec["components"]
+		_, hasWebhooks := v.spec["webhooks"]
+
+		switch {
+		case hasPaths:
+			v.report(SeverityError, "invalid-type", "/paths", "\"paths\" must be an object")
+		case version != "3.1":
+			v.report(SeverityError, "required-field", "", "missing required field \"paths\"")
+		case !hasComponents && !hasWebhooks:
+			v.report(SeverityError, "required-field", "", "at least one of \"paths\", \"components\" or \"webhooks\" is required")
+		}
+		return
+	}
+
+	operationIDs := make(map[string]string)
+	normalizedPaths := make(map[string]string)
+
+	for _, path := range sortedKeys(paths) {
+		if strings.HasPrefix(path, "x-") {
+			continue
+		}
+
+		pointer := "/paths/" + escapeJSONPointer(path)
+		if !strings.HasPrefix(path, "/") {
+			v.report(SeverityError, "path-format", pointer, "path %q must start with \"/\"", path)
+		}
+
+		normalized := pathParamPattern.ReplaceAllString(path, "{}")
+		if other, ok := normalizedPaths[normalized]; ok {
+			v.report(SeverityError, "ambiguous-path", pointer, "path %q is equivalent to path %q", path, other)
+		}
+		normalizedPaths[normalized] = path
+
+		pathItem, ok := paths[path].(map[string]any)
+		if !ok {
+			v.report(SeverityError, "invalid-type", pointer, "path item must be an object")
+			continue
+		}
+
+		for _, field := range sortedKeys(pathItem) {
+			if _, ok := pathItemFields[field]; !ok && !strings.HasPrefix(field, "x-") {
+				v.report(SeverityError, "unknown-field", pointer+"/"+escapeJSONPointer(field),
+					"unknown path item field %q", field)
+			}
+		}
+
+		pathParams := v.validateParameters(version, pointer+"/parameters", pathItem["parameters"])
+
+		for _, method := range httpMethods {
+			operation, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			opPointer := pointer + "/" + method
+			v.validateOperation(version, opPointer, operation)
+
+			if operationID, ok := operation["operationId"].(string); ok {
+				if other, exists := operationIDs[operationID]; exists {
+					v.report(SeverityError, "unique-operation-id", opPointer+"/operationId",
+						"operationId %q is already used by %s", operationID, other)
+				} else {
+					operationIDs[operationID] = strings.ToUpper(method) + " " + path
+				}
+			}
+
+			opParams := v.validateParameters(version, opPointer+"/parameters", operation["parameters"])
+			v.validatePathParameters(path, opPointer, pathParams, opParams)
+		}
+
+		for _, name := range sortedKeys(pathParams) {
+			if !strings.Contains(path, "{"+name+"}") {
+				v.report(SeverityError, "path-parameters", pointer+"/parameters",
+					"path parameter %q is defined but not used in path %q", name, path)
+			}
+		}
+	}
+}
+
+func (v *validator) validateOperation(version, pointer string, operation map[string]any) {
+	for _, field := range sortedKeys(operation) {
+		if _, ok := operationFields[field]; !ok && !strings.HasPrefix(field, "x-") {
+			v.report(SeverityError, "unknown-field", pointer+"/"+escapeJSONPointer(field), "unknown operation field %q", field)
+		}
+	}
+
+	responses, ok := operation["responses"].(map[string]any)
+	if !ok {
+		if version != "3.1" {
+			v.report(SeverityError, "required-field", pointer, "missing required field \"responses\"")
+		}
+		return
+	}
+
+	if len(responses) == 0 {
+		v.report(SeverityError, "required-field", pointer+"/responses", "at least one response is required")
+	}
+
+	for _, code := range sortedKeys(responses) {
+		if strings.HasPrefix(code, "x-") {
+			continue
+		}
+
+		responsePointer := pointer + "/responses/" + escapeJSONPointer(code)
+		if !responseCodePattern.MatchString(code) {
+			v.report(SeverityError, "response-code", responsePointer, "invalid response code %q", code)
+		}
+
+		response, ok := responses[code].(map[string]any)
+		if !ok {
+			v.report(SeverityError, "invalid-type", responsePointer, "response must be an object")
+			continue
+		}
+
+		if _, isRef := response["$ref"]; isRef {
+			continue
+		}
+
+		if _, ok := response["description"].(string); !ok {
+			v.report(SeverityError, "required-field", responsePointer, "missing required field \"description\"")
+		}
+	}
+}
+
+// validateParameters validates a list of parameters and returns the names of all path parameters.
+func (v *validator) validateParameters(version, pointer string, value any) map[string]struct{} {
+	pathParams := make(map[string]struct{})
+	if value == nil {
+		return pathParams
+	}
+
+	params, ok := value.([]any)
+	if !ok {
+		v.report(SeverityError, "invalid-type", pointer, "parameters must be an array")
+		return pathParams
+	}
+
+	validLocations := []string{"query", "header", "path", "cookie"}
+	if version == "2.0" {
+		validLocations = []string{"query", "header", "path", "formData", "body"}
+	}
+
+	seen := make(map[string]struct{})
+	for idx, param := range params {
+		paramPointer := fmt.Sprintf("%s/%d", pointer, idx)
+
+		p := deref(v.spec, param)
+		if p == nil {
+			continue // unresolvable references are reported by validateRefs
+		}
+
+		name, hasName := p["name"].(string)
+		in, hasIn := p["in"].(string)
+
+		if !hasName {
+			v.report(SeverityError, "required-field", paramPointer, "missing required parameter field \"name\"")
+		}
+
+		if !hasIn {
+			v.report(SeverityError, "required-field", paramPointer, "missing required parameter field \"in\"")
+			continue
+		}
+
+		if !slices.Contains(validLocations, in) {
+			v.report(SeverityError, "parameter-location", paramPointer+"/in",
+				"invalid parameter location %q, expected one of %s", in, strings.Join(validLocations, ", "))
+		}
+
+		if _, duplicate := seen[in+":"+name]; duplicate {
+			v.report(SeverityError, "unique-parameter", paramPointer, "duplicate %s parameter %q", in, name)
+		}
+		seen[in+":"+name] = struct{}{}
+
+		if in == "path" {
+			pathParams[name] = struct{}{}
+			if required, _ := p["required"].(bool); !required {
+				v.report(SeverityError, "path-parameter-required", paramPointer,
+					"path parameter %q must have \"required: true\"", name)
+			}
+		}
+
+		_, hasSchema := p["schema"]
+		_, hasContent := p["content"]
+		_, hasType := p["type"]
+
+		switch {
+		case version != "2.0" && hasSchema == hasContent:
+			v.report(SeverityError, "parameter-schema", paramPointer,
+				"parameter %q must have either \"schema\" or \"content\"", name)
+		case version == "2.0" && in == "body" && !hasSchema:
+			v.report(SeverityError, "parameter-schema", paramPointer, "body parameter %q must have a \"schema\"", name)
+		case version == "2.0" && in != "body" && !hasType:
+			v.report(SeverityError, "parameter-schema", paramPointer, "parameter %q must have a \"type\"", name)
+		}
+	}
+
+	return pathParams
+}
+
+// validatePathParameters checks that all variables of a path template are defined as path
+// parameters and that all path parameters are used in the path template.
+func (v *validator) validatePathParameters(path, pointer string, pathParams, opParams map[string]struct{}) {
+	templateParams := make(map[string]struct{})
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		templateParams[match[1]] = struct{}{}
+	}
+
+	for _, name := range sortedKeys(templateParams) {
+		_, onPath := pathParams[name]
+		_, onOperation := opParams[name]
+		if !onPath && !onOperation {
+			v.report(SeverityError, "path-parameters", pointer,
+				"path parameter %q is used in the path but not defined", name)
+		}
+	}
+
+	for _, name := range sortedKeys(opParams) {
+		if _, ok := templateParams[name]; !ok {
+			v.report(SeverityError, "path-parameters", pointer+"/parameters",
+				"path parameter %q is defined but not used in path %q", name, path)
+		}
+	}
+}
+
+var componentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+
+func (v *validator) validateComponentNames() {
+	components, _ := v.spec["components"].(map[string]any)
+	for _, section := range sortedKeys(components) {
+		if strings.HasPrefix(section, "x-") {
+			continue
+		}
+
+		entries, _ := components[section].(map[string]any)
+		for _, name := range sortedKeys(entries) {
+			if !componentNamePattern.MatchString(name) {
+				v.report(SeverityError, "component-name", "/components/"+escapeJSONPointer(section)+"/"+escapeJSONPointer(name),
+					"component name %q must match %s", name, componentNamePattern)
+			}
+		}
+	}
+}
+
+func (v *validator) validateSecurityRequirements(version string) {
+	schemes, _ := lookup(v.spec, "components", "securitySchemes").(map[string]any)
+	if version == "2.0" {
+		schemes, _ = v.spec["securityDefinitions"].(map[string]any)
+	}
+
+	check := func(pointer string, value any) {
+		requirements, _ := value.([]any)
+		for idx, requirement := range requirements {
+			requirementObj, _ := requirement.(map[string]any)
+			for _, name := range sortedKeys(requirementObj) {
+				if _, ok := schemes[name]; !ok {
+					v.report(SeverityError, "security-scheme", fmt.Sprintf("%s/%d/%s", pointer, idx, escapeJSONPointer(name)),
+						"security scheme %q is not defined", name)
+				}
+			}
+		}
+	}
+
+	check("/security", v.spec["security"])
+
+	paths, _ := v.spec["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		for _, method := range httpMethods {
+			if security := lookup(paths, path, method, "security"); security != nil {
+				check("/paths/"+escapeJSONPointer(path)+"/"+method+"/security", security)
+			}
+		}
+	}
+}
+
+func (v *validator) validateServers(pointer string, value any) {
+	servers, _ := value.([]any)
+	for idx, server := range servers {
+		serverObj, _ := server.(map[string]any)
+		serverURL, ok := serverObj["url"].(string)
+		if !ok {
+			v.report(SeverityError, "required-field", fmt.Sprintf("%s/%d", pointer, idx), "missing required field \"url\"")
+			continue
+		}
+
+		variables, _ := serverObj["variables"].(map[string]any)
+		for _, match := range pathParamPattern.FindAllStringSubmatch(serverURL, -1) {
+			if _, ok := variables[match[1]]; !ok {
+				v.report(SeverityError, "server-variables", fmt.Sprintf("%s/%d/url", pointer, idx),
+					"server variable %q is not defined", match[1])
+			}
+		}
+	}
+}
+
+// validateRefs checks that all local references can be resolved.
+func (v *validator) validateRefs() {
+	var walk func(value any, pointer string)
+	walk = func(value any, pointer string) {
+		switch val := value.(type) {
+		case map[string]any:
+			if ref, ok := val["$ref"].(string); ok {
+				refPointer := pointer + "/$ref"
+				switch {
+				case !strings.HasPrefix(ref, "#"):
+					v.report(SeverityWarning, "external-ref", refPointer,
+						"external reference %q cannot be resolved in a single document", ref)
+				default:
+					if _, ok := resolveRef(v.spec, ref); !ok {
+						v.report(SeverityError, "unresolvable-ref", refPointer, "reference %q cannot be resolved", ref)
+					}
+				}
+			}
+
+			for _, key := range sortedKeys(val) {
+				// Examples may contain arbitrary data, including "$ref" fields. OpenAPI 3 example objects
+				// in "examples" maps may be references themselves, so only schema examples are skipped.
+				_, isSchemaExamples := val[key].([]any)
+				_, isSwagger2 := v.spec["swagger"]
+				if key == "example" || key == "examples" && (isSchemaExamples || isSwagger2) {
+					continue
+				}
+				walk(val[key], pointer+"/"+escapeJSONPointer(key))
+			}
+		case []any:
+			for idx, elem := range val {
+				walk(elem, fmt.Sprintf("%s/%d", pointer, idx))
+			}
+		}
+	}
+
+	walk(v.spec, "")
+}
+
+// validateSchemas checks that required properties of schemas are defined.
+func (v *validator) validateSchemas() {
+	for sectionRef, schemas := range componentSectionsOf(v.spec) {
+		if sectionRef != "#/components/schemas" && sectionRef != "#/definitions" {
+			continue
+		}
+
+		for _, name := range sortedKeys(schemas) {
+			schema, _ := schemas[name].(map[string]any)
+			properties, hasProperties := schema["properties"].(map[string]any)
+			_, hasAllOf := schema["allOf"]
+			_, hasAdditional := schema["additionalProperties"]
+			if !hasProperties || hasAllOf || hasAdditional {
+				continue
+			}
+
+			required, _ := schema["required"].([]any)
+			for _, property := range required {
+				if _, ok := properties[fmt.Sprint(property)]; !ok {
+					v.report(SeverityWarning, "required-properties",
+						strings.TrimPrefix(sectionRef, "#")+"/"+escapeJSONPointer(name)+"/required",
+						"required property %q is not defined", property)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package go_swagger_ui
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+// invalidTestSpec is missing the required field "info.version" and references another file.
+const invalidTestSpec = `openapi: 3.0.3
+info:
+  title: Pets
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          $ref: ./responses.yaml
+`
+
+func TestSpecValidationPositions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"openapi.yaml":   {Data: []byte(invalidTestSpec)},
+		"responses.yaml": {Data: []byte("description: ok\n")},
+	}
+
+	tests := []struct {
+		name          string
+		cfg           *uiConfig
+		expectedLines bool
+	}{
+		{
+			name:          "original document",
+			cfg:           &uiConfig{specValidation: true},
+			expectedLines: true,
+		},
+		{
+			name:          "bundled document",
+			cfg:           &uiConfig{specValidation: true, specFS: fsys, specFSName: "openapi.yaml"},
+			expectedLines: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(invalidTestSpec)
+			if tt.cfg.specFS != nil {
+				var err error
+				if data, err = loadSpecFile(tt.cfg); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := prepareSpec(data, tt.cfg)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+
+			for _, d := range validationErr.Diagnostics {
+				if hasLine := d.Line > 0; hasLine != tt.expectedLines {
+					t.Errorf("expected line to be set: %t, got diagnostic %s", tt.expectedLines, d)
+				}
+			}
+		})
+	}
+}
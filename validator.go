@@ -0,0 +1,281 @@
+package go_swagger_ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+)
+
+const (
+	// validatorEndpoint is the file name the local validator is served on.
+	validatorEndpoint = "_validator"
+
+	// specEndpoint is the file name the spec is served on when the local validator is enabled.
+	specEndpoint = "_spec.json"
+)
+
+// LocalValidator configures the validator that is hosted by the handler. See WithLocalValidator.
+type LocalValidator struct {
+	// Timeout is the maximum duration of fetching a spec from a URL configured with WithSpecURL
+	// or WithSpecURLs. Default is 10 seconds.
+	Timeout time.Duration
+
+	// MaxSpecSize is the maximum size of a spec fetched from a URL in bytes. Default is 10 MiB.
+	MaxSpecSize int64
+
+	// Transport is used to fetch specs from URLs. Default is http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// WithLocalValidator hosts a validator that is compatible with the validator badge of Swagger UI
+// and points validatorUrl to it. Specs are validated with ValidateSpec, so the badge works
+// without access to swagger.io's online validator (e.g., in air-gapped environments).
+//
+// Swagger UI only shows the badge for specs it loads from a URL. If the spec is set with WithSpec or
+// WithSpecFilePath, it is therefore served on a separate endpoint and loaded from there. Only this
+// spec and specs configured with WithSpecURL or WithSpecURLs are validated, so the validator cannot
+// be used to make requests to arbitrary URLs. Please note that Swagger UI hides the badge if the
+// spec URL contains "localhost" or "127.0.0.1".
+func WithLocalValidator(validator LocalValidator) Option {
+	return func(cfg *uiConfig) {
+		if validator.Timeout <= 0 {
+			validator.Timeout = 10 * time.Second
+		}
+
+		if validator.MaxSpecSize <= 0 {
+			validator.MaxSpecSize = 10 << 20
+		}
+
+		if validator.Transport == nil {
+			validator.Transport = http.DefaultTransport
+		}
+
+		cfg.localValidator = configValue[LocalValidator]{Value: validator, IsSet: true}
+	}
+}
+
+// errValidationNotAllowed is returned if the validator is asked to validate a spec
+// that is not served by the handler or configured with WithSpecURL or WithSpecURLs.
+var errValidationNotAllowed = errors.New("validating this URL is not allowed")
+
+// validatorMessage has the format of the schema validation messages of swagger.io's online validator.
+type validatorMessage struct {
+	Level   Severity `json:"level"`
+	Domain  string   `json:"domain"`
+	Keyword string   `json:"keyword"`
+	Message string   `json:"message"`
+	Pointer string   `json:"pointer,omitempty"`
+	Line    int      `json:"line,omitempty"`
+	Column  int      `json:"column,omitempty"`
+}
+
+// validatorResult has the format of the debug output of swagger.io's online validator.
+type validatorResult struct {
+	Messages                 []string           `json:"messages,omitempty"`
+	SchemaValidationMessages []validatorMessage `json:"schemaValidationMessages,omitempty"`
+}
+
+func (r validatorResult) valid() bool {
+	if len(r.Messages) > 0 {
+		return false
+	}
+
+	for _, msg := range r.SchemaValidationMessages {
+		if msg.Level == SeverityError {
+			return false
+		}
+	}
+
+	return true
+}
+
+type localValidator struct {
+	cfg    *uiConfig
+	client *http.Client
+}
+
+func newLocalValidator(cfg *uiConfig) *localValidator {
+	settings := cfg.localValidator.Value
+
+	return &localValidator{
+		cfg: cfg,
+		client: &http.Client{
+			Transport: settings.Transport,
+			Timeout:   settings.Timeout,
+		},
+	}
+}
+
+// ServeHTTP serves the validator badge on "_validator?url=<spec URL>" and the diagnostics
+// as JSON on "_validator/debug?url=<spec URL>", like swagger.io's online validator.
+func (v *localValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		sendStatus(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var result validatorResult
+
+	spec, err := v.loadSpec(r, r.URL.Query().Get("url"))
+	switch {
+	case errors.Is(err, errValidationNotAllowed):
+		sendStatus(w, http.StatusForbidden)
+		return
+	case err != nil:
+		slog.Error("validator cannot load spec", "err", err.Error())
+		result.Messages = append(result.Messages, err.Error())
+	default:
+		result = validateForBadge(spec)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if path.Base(r.URL.Path) == "debug" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	if err := renderBadge(w, result.valid()); err != nil {
+		slog.Error("cannot render validator badge", "err", err.Error())
+	}
+}
+
+// loadSpec returns the spec at the given URL. The URL is resolved relative to the validator
+// URL. Only the spec served by the handler and specs configured with WithSpecURL or
+// WithSpecURLs are loaded.
+func (v *localValidator) loadSpec(r *http.Request, rawURL string) ([]byte, error) {
+	scheme, host := requestOrigin(r, false)
+	base := &url.URL{Scheme: scheme, Host: host, Path: r.URL.Path}
+
+	target, err := base.Parse(rawURL)
+	if rawURL == "" || err != nil {
+		return nil, errValidationNotAllowed
+	}
+
+	if target.Host == host && isSpecPath(v.cfg, target.Path) && len(v.cfg.currentSpec()) > 0 {
+		return specForRequest(r, v.cfg)
+	}
+
+	for _, specURL := range specURLs(v.cfg) {
+		allowed, err := base.Parse(specURL)
+		if err == nil && allowed.String() == target.String() {
+			return v.fetchSpec(r, target.String())
+		}
+	}
+
+	return nil, errValidationNotAllowed
+}
+
+func (v *localValidator) fetchSpec(r *http.Request, specURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch spec: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch spec: unexpected status code %d", resp.StatusCode)
+	}
+
+	maxSize := v.cfg.localValidator.Value.MaxSpecSize
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch spec: %w", err)
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("cannot fetch spec: spec is larger than %d bytes", maxSize)
+	}
+
+	return data, nil
+}
+
+func validateForBadge(spec []byte) validatorResult {
+	diagnostics, err := ValidateSpec(spec)
+	if err != nil {
+		return validatorResult{Messages: []string{err.Error()}}
+	}
+
+	var result validatorResult
+	for _, d := range diagnostics {
+		result.SchemaValidationMessages = append(result.SchemaValidationMessages, validatorMessage{
+			Level:   d.Severity,
+			Domain:  "validation",
+			Keyword: d.Rule,
+			Message: d.Message,
+			Pointer: d.Pointer,
+			Line:    d.Line,
+			Column:  d.Column,
+		})
+	}
+
+	return result
+}
+
+var badgeTemplate = template.Must(template.New("badge").Parse(
+	`<svg xmlns="http://www.w3.org/2000/svg" width="{{ .Width }}" height="20" role="img" aria-label="validator: {{ .Status }}">` +
+		`<rect width="60" height="20" fill="#555"/>` +
+		`<rect x="60" width="{{ .StatusWidth }}" height="20" fill="{{ .Color }}"/>` +
+		`<g fill="#fff" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+		`<text x="6" y="14">validator</text>` +
+		`<text x="66" y="14">{{ .Status }}</text>` +
+		`</g></svg>`,
+))
+
+func renderBadge(w io.Writer, valid bool) error {
+	status, color, statusWidth := "valid", "#4c1", 40
+	if !valid {
+		status, color, statusWidth = "invalid", "#e05d44", 48
+	}
+
+	return badgeTemplate.Execute(w, struct {
+		Status, Color      string
+		Width, StatusWidth int
+	}{
+		Status:      status,
+		Color:       color,
+		Width:       60 + statusWidth,
+		StatusWidth: statusWidth,
+	})
+}
+
+// validatorURL returns the URL of the local validator relative to the index.html
+// page or the configured validator URL if the local validator is disabled.
+func validatorURL(cfg *uiConfig) string {
+	if !cfg.localValidator.IsSet {
+		return fromStringConfigValue(cfg.validatorUrl)
+	}
+
+	return cfg.basePath + validatorEndpoint
+}
+
+// isSpecPath reports whether a URL path points to the spec served by the handler.
+func isSpecPath(cfg *uiConfig, urlPath string) bool {
+	if path.Base(urlPath) == specEndpoint {
+		return true
+	}
+
+	if !servesSpecFiles(cfg) {
+		return false
+	}
+
+	_, root := specFilesRoot(cfg)
+	filePath, ok := endpointSubPath(urlPath, filesEndpoint)
+
+	return ok && filePath == "/"+root
+}
@@ -0,0 +1,137 @@
+package go_swagger_ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLocalValidator(t *testing.T) {
+	const invalidSpec = "openapi: 3.0.3\npaths: {}\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/valid.yaml":
+			w.Write([]byte(testSpec))
+		case "/invalid.yaml":
+			w.Write([]byte(invalidSpec))
+		case "/large.yaml":
+			w.Write([]byte(testSpec + strings.Repeat("#", 1024)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	embedded := NewHandler(WithSpec([]byte(testSpec)), WithLocalValidator(LocalValidator{}))
+	embeddedInvalid := NewHandler(WithSpec([]byte(invalidSpec)), WithLocalValidator(LocalValidator{}))
+	remote := NewHandler(
+		WithSpecURLs("", []SpecURL{
+			{Name: "valid", URL: server.URL + "/valid.yaml"},
+			{Name: "invalid", URL: server.URL + "/invalid.yaml"},
+			{Name: "large", URL: server.URL + "/large.yaml"},
+			{Name: "missing", URL: server.URL + "/missing.yaml"},
+		}),
+		WithLocalValidator(LocalValidator{MaxSpecSize: int64(len(testSpec) + 512)}),
+	)
+
+	tests := []struct {
+		name             string
+		handler          http.Handler
+		specURL          string
+		expectedStatus   int
+		expectedValid    bool
+		expectedMessages []string
+	}{
+		{"embedded spec", embedded, "_spec.json", http.StatusOK, true, nil},
+		{"absolute URL of the embedded spec", embedded, "http://example.com/_spec.json", http.StatusOK, true, nil},
+		{"invalid embedded spec", embeddedInvalid, "_spec.json", http.StatusOK, false, nil},
+		{"spec URL", remote, server.URL + "/valid.yaml", http.StatusOK, true, nil},
+		{"invalid spec URL", remote, server.URL + "/invalid.yaml", http.StatusOK, false, nil},
+		{"spec larger than the maximum size", remote, server.URL + "/large.yaml", http.StatusOK, false, []string{"cannot fetch spec: spec is larger than"}},
+		{"spec URL not found", remote, server.URL + "/missing.yaml", http.StatusOK, false, []string{"cannot fetch spec: unexpected status code 404"}},
+		{"spec of another host", embedded, "http://other.example.com/_spec.json", http.StatusForbidden, false, nil},
+		{"URL that is not configured", remote, server.URL + "/other.yaml", http.StatusForbidden, false, nil},
+		{"missing URL", embedded, "", http.StatusForbidden, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := "?url=" + url.QueryEscape(tt.specURL)
+
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/_validator"+query, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			status := "invalid"
+			if tt.expectedValid {
+				status = "valid"
+			}
+			if contentType := rec.Header().Get("Content-Type"); contentType != "image/svg+xml" {
+				t.Errorf("expected an SVG badge, got content type %q", contentType)
+			}
+			if body := rec.Body.String(); !strings.Contains(body, `aria-label="validator: `+status+`"`) {
+				t.Errorf("expected badge %q, got %s", status, body)
+			}
+
+			rec = httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/_validator/debug"+query, nil))
+
+			var result validatorResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("cannot parse debug output %s: %v", rec.Body, err)
+			}
+
+			if result.valid() != tt.expectedValid {
+				t.Errorf("expected valid to be %v, got %+v", tt.expectedValid, result)
+			}
+			if !tt.expectedValid && len(tt.expectedMessages) == 0 && len(result.SchemaValidationMessages) == 0 {
+				t.Error("expected schema validation messages")
+			}
+			if len(result.Messages) != len(tt.expectedMessages) {
+				t.Fatalf("expected messages %v, got %v", tt.expectedMessages, result.Messages)
+			}
+			for idx, expected := range tt.expectedMessages {
+				if !strings.HasPrefix(result.Messages[idx], expected) {
+					t.Errorf("expected message %q, got %q", expected, result.Messages[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestLocalValidatorRejectsOtherMethods(t *testing.T) {
+	handler := NewHandler(WithSpec([]byte(testSpec)), WithLocalValidator(LocalValidator{}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/_validator?url=_spec.json", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestValidateForBadge(t *testing.T) {
+	result := validateForBadge([]byte(`{"openapi": "3.0.3", "info": {"title": "Pets", "version": "1"}, "paths": {"pets": {}}}`))
+	if result.valid() {
+		t.Fatalf("expected an invalid result, got %+v", result)
+	}
+
+	msg := result.SchemaValidationMessages[0]
+	if msg.Level != SeverityError || msg.Domain != "validation" || msg.Keyword == "" || msg.Pointer == "" {
+		t.Errorf("expected a message in the format of the online validator, got %+v", msg)
+	}
+
+	if result := validateForBadge([]byte("{")); result.valid() || len(result.Messages) != 1 {
+		t.Errorf("expected a message for a spec that cannot be parsed, got %+v", result)
+	}
+}